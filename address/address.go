@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"errors"
 	"fmt"
+	"io"
 )
 
 var PrefixAddress string = "xel"
@@ -12,6 +13,11 @@ var TestnetPrefixAddress string = "xet"
 var ExtraDataLimit = 1024
 
 var ErrIntegratedDataLimit = errors.New("invalid data in integrated address, maximum size reached")
+var ErrAddressTrailingData = errors.New("invalid address, unexpected data after the address")
+
+func ErrInvalidPrefix(hrp string) error {
+	return fmt.Errorf("invalid address prefix %s", hrp)
+}
 
 type Address struct {
 	publicKey    []byte
@@ -20,11 +26,26 @@ type Address struct {
 	extraData    *DataElement
 }
 
+// Normal address of a 32 bytes public key.
+func NewAddress(publicKey []byte, mainnet bool) (addr *Address, err error) {
+	if len(publicKey) != 32 {
+		err = fmt.Errorf("invalid public key size %d", len(publicKey))
+		return
+	}
+
+	addr = &Address{
+		publicKey: append([]byte{}, publicKey...),
+		isMainnet: mainnet,
+	}
+
+	return
+}
+
 func NewAddressFromData(data []byte, hrp string) (addr *Address, err error) {
 	reader := bytes.NewReader(data)
 
 	publicKey := make([]byte, 32)
-	_, err = reader.Read(publicKey)
+	_, err = io.ReadFull(reader, publicKey)
 	if err != nil {
 		return
 	}
@@ -43,14 +64,17 @@ func NewAddressFromData(data []byte, hrp string) (addr *Address, err error) {
 	case 1:
 		integrated = true
 
-		dataValueReader := &DataValueReader{Reader: reader}
+		// untrusted data, stop reading as soon as it's over the limit
+		limits := DefaultReadLimits
+		limits.MaxSize = ExtraDataLimit
+		dataValueReader := &DataValueReader{Reader: reader, Limits: limits}
 		extraData, err = dataValueReader.Read()
-		if err != nil {
-			return
+		var readErr *ReadError
+		if errors.As(err, &readErr) && readErr.Err == ErrMaxSize {
+			readErr.Err = ErrIntegratedDataLimit
 		}
 
-		if reader.Size() > int64(ExtraDataLimit) {
-			err = ErrIntegratedDataLimit
+		if err != nil {
 			return
 		}
 	default:
@@ -58,6 +82,11 @@ func NewAddressFromData(data []byte, hrp string) (addr *Address, err error) {
 		return
 	}
 
+	if reader.Len() > 0 {
+		err = ErrAddressTrailingData
+		return
+	}
+
 	addr = &Address{
 		isMainnet:    hrp == PrefixAddress,
 		publicKey:    publicKey,
@@ -75,6 +104,7 @@ func NewAddressFromString(address string) (addr *Address, err error) {
 	}
 
 	if hrp != PrefixAddress && hrp != TestnetPrefixAddress {
+		err = ErrInvalidPrefix(hrp)
 		return
 	}
 
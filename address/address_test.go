@@ -1,11 +1,14 @@
 package address
 
 import (
+	"errors"
 	"fmt"
+	"strings"
 	"testing"
 )
 
 var MAINNET_ADDR = "xel:ys4peuzztwl67rzhsdu0yxfzwcfmgt85uu53hycpeeary7n8qvysqmxznt0"
+var INTEGRATED_ADDR = "xet:6eadzwf5xdacts6fs4y3csmnsmy4mcxewqt3xyygwfx0hm0tm32szqsrqyzkjar9d4esyqgpq4ehwmmjvsqqypgpq45x2mrvduqqzpthdaexceqpq4mk7unywvqsgqqpq4yx2mrvduqqzp2hdaexceqqqyzxvun0d5qqzp2cg4xyj5ct5udlg"
 
 func TestAddressFromString(t *testing.T) {
 	address, err := NewAddressFromString(MAINNET_ADDR)
@@ -42,7 +45,7 @@ func TestInvalidAddress(t *testing.T) {
 }
 
 func TestIntegratedAddress(t *testing.T) {
-	address, err := NewAddressFromString("xet:6eadzwf5xdacts6fs4y3csmnsmy4mcxewqt3xyygwfx0hm0tm32szqsrqyzkjar9d4esyqgpq4ehwmmjvsqqypgpq45x2mrvduqqzpthdaexceqpq4mk7unywvqsgqqpq4yx2mrvduqqzp2hdaexceqqqyzxvun0d5qqzp2cg4xyj5ct5udlg")
+	address, err := NewAddressFromString(INTEGRATED_ADDR)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -70,6 +73,16 @@ func TestIntegratedAddress(t *testing.T) {
 			fmt.Println(value)
 		}
 	}
+
+	// fields are written back in the order of the node
+	addr, err := address.Format()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if addr != INTEGRATED_ADDR {
+		t.Errorf("Expected %s, got %s", INTEGRATED_ADDR, addr)
+	}
 }
 
 func TestAddressExtraDataValue(t *testing.T) {
@@ -154,6 +167,36 @@ func TestAddressExtraDataFields(t *testing.T) {
 	t.Logf("%+v\n", extraData)
 }
 
+func TestAddressExtraDataFieldsFormat(t *testing.T) {
+	address, err := NewAddressFromString(MAINNET_ADDR)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	fields := make(map[DataValue]DataElement, 0)
+	for i := uint8(0); i < 16; i++ {
+		fields[i] = DataElement{Value: fmt.Sprintf("item %d", i)}
+	}
+
+	address.SetExtraData(&DataElement{Fields: fields})
+
+	expected, err := address.Format()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 20; i++ {
+		addr, err := address.Format()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if addr != expected {
+			t.Fatalf("Expected %s, got %s", expected, addr)
+		}
+	}
+}
+
 func TestAddressClearExtraData(t *testing.T) {
 	address, err := NewAddressFromString(MAINNET_ADDR)
 	if err != nil {
@@ -185,3 +228,75 @@ func TestAddressClearExtraData(t *testing.T) {
 		t.Logf("Expected %s, got %s", MAINNET_ADDR, addr)
 	}
 }
+
+func TestIntegratedDataLimit(t *testing.T) {
+	address, err := NewAddressFromString(MAINNET_ADDR)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var items []DataElement
+	for i := 0; i < 5; i++ {
+		items = append(items, DataElement{Value: strings.Repeat("a", 250)})
+	}
+
+	address.SetExtraData(&DataElement{Array: items})
+	addr, err := address.Format()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = NewAddressFromString(addr)
+	var readErr *ReadError
+	if !errors.Is(err, ErrIntegratedDataLimit) || !errors.As(err, &readErr) {
+		t.Errorf("Expected %s, got %v", ErrIntegratedDataLimit, err)
+	}
+}
+
+func TestUnknownPrefix(t *testing.T) {
+	_, decoded, err := decode(MAINNET_ADDR)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	addr, err := encode("abc", decoded)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	valid, err := IsValidAddress(addr)
+	if valid || err == nil {
+		t.Errorf("Expected %s to be invalid", addr)
+	}
+}
+
+func FuzzNewAddressFromString(f *testing.F) {
+	f.Add(MAINNET_ADDR)
+	f.Add("xet:6eadzwf5xdacts6fs4y3csmnsmy4mcxewqt3xyygwfx0hm0tm32szqsrqyzkjar9d4esyqgpq4ehwmmjvsqqypgpq45x2mrvduqqzpthdaexceqpq4mk7unywvqsgqqpq4yx2mrvduqqzp2hdaexceqqqyzxvun0d5qqzp2cg4xyj5ct5udlg")
+
+	f.Fuzz(func(t *testing.T, data string) {
+		address, err := NewAddressFromString(data)
+		if err != nil {
+			return
+		}
+
+		addr, err := address.Format()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		addressCopy, err := NewAddressFromString(addr)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		addrCopy, err := addressCopy.Format()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if addr != addrCopy {
+			t.Errorf("Expected %s, got %s", addr, addrCopy)
+		}
+	})
+}
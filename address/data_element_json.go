@@ -0,0 +1,241 @@
+package address
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strconv"
+)
+
+// The daemon and the wallet use the untagged JSON form of a data element:
+// a value is a JSON bool, string or number, an array is a JSON array and fields are a JSON object.
+// Hashes are written as hex strings and u128 values as plain numbers.
+//
+// The JSON form has no value types, so decoding can't give back the original types:
+// numbers are decoded to the smallest unsigned type holding them, from uint8 up to u128,
+// hashes stay hex strings and field keys are strings. A uint64 amount of 100 comes back as a uint8.
+// The binary encoding, and so an integrated address or a signature, changes with the types.
+// Keep the binary form when the exact bytes matter, and use Unmarshal to read values into typed fields.
+
+func ErrInvalidNumber(value string) error {
+	return fmt.Errorf("invalid number %s, expected an unsigned integer of up to 128 bits", value)
+}
+
+func (d DataElement) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	err := d.writeJSON(&buf)
+	if err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+func (d DataElement) writeJSON(buf *bytes.Buffer) (err error) {
+	switch {
+	case d.Value != nil:
+		var data []byte
+		data, err = valueToJSON(d.Value)
+		if err != nil {
+			return
+		}
+
+		buf.Write(data)
+	case d.Array != nil:
+		buf.WriteByte('[')
+		for i, item := range d.Array {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+
+			err = item.writeJSON(buf)
+			if err != nil {
+				return
+			}
+		}
+		buf.WriteByte(']')
+	case d.Fields != nil:
+		// same order as the binary encoding
+		var keys []fieldKey
+		keys, err = d.fieldKeys()
+		if err != nil {
+			return
+		}
+
+		buf.WriteByte('{')
+		for i, key := range keys {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+
+			var name string
+			name, err = keyToString(key.value)
+			if err != nil {
+				return
+			}
+
+			var data []byte
+			data, err = json.Marshal(name)
+			if err != nil {
+				return
+			}
+
+			buf.Write(data)
+			buf.WriteByte(':')
+
+			err = d.Fields[key.value].writeJSON(buf)
+			if err != nil {
+				return
+			}
+		}
+		buf.WriteByte('}')
+	default:
+		buf.WriteString("null")
+	}
+
+	return
+}
+
+func valueToJSON(value DataValue) ([]byte, error) {
+	switch value := value.(type) {
+	case bool, string:
+		return json.Marshal(value)
+	case Hash:
+		return json.Marshal(hex.EncodeToString(value[:]))
+	}
+
+	number, err := keyToString(value)
+	if err != nil {
+		return nil, err
+	}
+
+	return []byte(number), nil
+}
+
+func keyToString(value DataValue) (string, error) {
+	switch value := value.(type) {
+	case bool:
+		return strconv.FormatBool(value), nil
+	case string:
+		return value, nil
+	case uint8:
+		return strconv.FormatUint(uint64(value), 10), nil
+	case uint16:
+		return strconv.FormatUint(uint64(value), 10), nil
+	case uint32:
+		return strconv.FormatUint(uint64(value), 10), nil
+	case uint64:
+		return strconv.FormatUint(value, 10), nil
+	case big.Int:
+		if value.Sign() < 0 || value.BitLen() > 128 {
+			return "", ErrUnsupportedValue(value.String())
+		}
+
+		return value.String(), nil
+	case Hash:
+		return hex.EncodeToString(value[:]), nil
+	default:
+		return "", ErrUnsupportedValue(value)
+	}
+}
+
+// Object keys are kept in order in Keys.
+func (d *DataElement) UnmarshalJSON(data []byte) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	element, err := readJSON(decoder)
+	if err != nil {
+		return err
+	}
+
+	*d = element
+	return nil
+}
+
+func readJSON(decoder *json.Decoder) (element DataElement, err error) {
+	token, err := decoder.Token()
+	if err != nil {
+		return
+	}
+
+	switch token := token.(type) {
+	case nil:
+	case json.Delim:
+		switch token {
+		case '[':
+			element.Array = make([]DataElement, 0)
+			for decoder.More() {
+				var item DataElement
+				item, err = readJSON(decoder)
+				if err != nil {
+					return
+				}
+
+				element.Array = append(element.Array, item)
+			}
+		case '{':
+			element.Fields = make(map[DataValue]DataElement)
+			element.Keys = make([]DataValue, 0)
+			for decoder.More() {
+				var key json.Token
+				key, err = decoder.Token()
+				if err != nil {
+					return
+				}
+
+				var item DataElement
+				item, err = readJSON(decoder)
+				if err != nil {
+					return
+				}
+
+				// a duplicated key keeps its first position and its last value
+				if _, ok := element.Fields[key]; !ok {
+					element.Keys = append(element.Keys, key)
+				}
+
+				element.Fields[key] = item
+			}
+		}
+
+		// closing delimiter
+		_, err = decoder.Token()
+	case bool:
+		element.Value = token
+	case string:
+		element.Value = token
+	case json.Number:
+		element.Value, err = numberFromJSON(token.String())
+	default:
+		err = ErrUnsupportedValue(token)
+	}
+
+	return
+}
+
+func numberFromJSON(value string) (DataValue, error) {
+	number, err := strconv.ParseUint(value, 10, 64)
+	if err == nil {
+		switch {
+		case number <= 0xff:
+			return uint8(number), nil
+		case number <= 0xffff:
+			return uint16(number), nil
+		case number <= 0xffffffff:
+			return uint32(number), nil
+		default:
+			return number, nil
+		}
+	}
+
+	var bigNumber big.Int
+	_, ok := bigNumber.SetString(value, 10)
+	if !ok || bigNumber.Sign() < 0 || bigNumber.BitLen() > 128 {
+		return nil, ErrInvalidNumber(value)
+	}
+
+	return bigNumber, nil
+}
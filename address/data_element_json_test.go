@@ -0,0 +1,128 @@
+package address
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"math/big"
+	"testing"
+)
+
+func TestDataElementJSON(t *testing.T) {
+	// fields are written back in the order of the object
+	data := `{"items":[true,"a",null,70000],"id":7,"big":340282366920938463463374607431768211455,"hash":"0000000000000000000000000000000000000000000000000000000000000000","amount":100000000000}`
+
+	var element DataElement
+	err := json.Unmarshal([]byte(data), &element)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if element.Fields["id"].Value != uint8(7) || element.Fields["amount"].Value != uint64(100000000000) {
+		t.Errorf("Unexpected fields %+v", element.Fields)
+	}
+
+	max, _ := new(big.Int).SetString("340282366920938463463374607431768211455", 10)
+	value, ok := element.Fields["big"].Value.(big.Int)
+	if !ok || value.Cmp(max) != 0 {
+		t.Errorf("Expected u128 %s, got %v", max, element.Fields["big"].Value)
+	}
+
+	items := element.Fields["items"].Array
+	if len(items) != 4 || items[0].Value != true || items[1].Value != "a" || items[2].Value != nil || items[3].Value != uint32(70000) {
+		t.Errorf("Unexpected array %+v", items)
+	}
+
+	encoded, err := json.Marshal(element)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(encoded) != data {
+		t.Errorf("Expected %s, got %s", data, encoded)
+	}
+}
+
+func TestDataElementJSONValues(t *testing.T) {
+	var hash Hash
+	hash[0] = 0xab
+
+	fields := make(map[DataValue]DataElement, 0)
+	fields[uint16(2)] = DataElement{Value: hash}
+	fields[true] = DataElement{Value: *big.NewInt(5)}
+
+	encoded, err := json.Marshal(&DataElement{Fields: fields})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `{"true":5,"2":"ab00000000000000000000000000000000000000000000000000000000000000"}`
+	if string(encoded) != expected {
+		t.Errorf("Expected %s, got %s", expected, encoded)
+	}
+
+	for _, data := range []string{`-1`, `1.5`, `340282366920938463463374607431768211456`, `[1,-2]`} {
+		var element DataElement
+		err = json.Unmarshal([]byte(data), &element)
+		if err == nil {
+			t.Errorf("Expected an error for %s, got %+v", data, element)
+		}
+	}
+
+	_, err = json.Marshal(DataElement{Value: int(1)})
+	if err == nil {
+		t.Error("Expected an unsupported value error")
+	}
+}
+
+func TestDataElementJSONTypesLost(t *testing.T) {
+	var hash Hash
+	hash[0] = 0xab
+
+	fields := map[DataValue]DataElement{uint64(1): {Value: uint64(100)}, "asset": {Value: hash}}
+	element := DataElement{Fields: fields, Keys: []DataValue{uint64(1), "asset"}}
+
+	encoded, err := json.Marshal(element)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var decoded DataElement
+	err = json.Unmarshal(encoded, &decoded)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if decoded.Fields["1"].Value != uint8(100) {
+		t.Errorf("Expected uint8 100, got %T %v", decoded.Fields["1"].Value, decoded.Fields["1"].Value)
+	}
+
+	if decoded.Fields["asset"].Value != hex.EncodeToString(hash[:]) {
+		t.Errorf("Expected a hex string, got %T %v", decoded.Fields["asset"].Value, decoded.Fields["asset"].Value)
+	}
+
+	original, err := writeDataElement(element)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := writeDataElement(decoded)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if bytes.Equal(original, data) {
+		t.Error("Expected a different binary encoding")
+	}
+
+	// typed values are still read back
+	var values struct {
+		Amount uint64 `xelis:"1"`
+		Asset  Hash   `xelis:"asset"`
+	}
+
+	err = Unmarshal(decoded, &values)
+	if err != nil || values.Amount != 100 || values.Asset != hash {
+		t.Errorf("Unexpected values %+v: %v", values, err)
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"io"
 	"math/big"
+	"sort"
 )
 
 type ValueType int
@@ -29,6 +30,7 @@ var DataElementArray DataElementType = 1
 var DataElementFields DataElementType = 2
 
 var ErrMaxStringSize = errors.New("string max limit is 255 bytes")
+var ErrMaxItemsSize = errors.New("array and fields max limit is 255 items")
 
 func ErrUnsupportedValue(value DataValue) error {
 	return fmt.Errorf("unsupported value type %v", value)
@@ -40,6 +42,9 @@ type DataElement struct {
 	Value  DataValue
 	Array  []DataElement
 	Fields map[DataValue]DataElement
+	// Order of the fields. The node keeps fields in insertion order and writes them in that order.
+	// Fields missing from Keys are written after, sorted by their key encoding so the output is stable.
+	Keys []DataValue
 }
 
 // use this function to convert in a valid map for json.Marshal()
@@ -77,12 +82,93 @@ func (d DataElement) ToMap() map[string]interface{} {
 	return result
 }
 
+// Limits of a single DataValueReader.Read, checked while reading. A zero value uses the default limit.
+type ReadLimits struct {
+	// Max nesting of arrays and fields. Defaults to 32.
+	MaxDepth int
+	// Max number of elements, including array items and field values. Defaults to 4096.
+	MaxElements int
+	// Max number of bytes read. Defaults to 65536.
+	MaxSize int
+}
+
+var DefaultReadLimits = ReadLimits{MaxDepth: 32, MaxElements: 4096, MaxSize: 65536}
+
+var ErrMaxDepth = errors.New("data element max depth reached")
+var ErrMaxElements = errors.New("data element max elements reached")
+var ErrMaxSize = errors.New("data element max size reached")
+var ErrInvalidElementType = errors.New("invalid data element type")
+var ErrInvalidValueType = errors.New("invalid data value type")
+var ErrInvalidBool = errors.New("invalid bool value")
+
+// u128 values can't be map keys in Go.
+var ErrUnsupportedKey = errors.New("unsupported u128 fields key")
+
+// Error of DataValueReader.Read with the offset in the reader where decoding failed.
+type ReadError struct {
+	Offset int64
+	Err    error
+}
+
+func (e *ReadError) Error() string {
+	return fmt.Sprintf("invalid data element at offset %d: %s", e.Offset, e.Err)
+}
+
+func (e *ReadError) Unwrap() error {
+	return e.Err
+}
+
 type DataValueReader struct {
 	Reader *bytes.Reader
+	Limits ReadLimits
+
+	limits   ReadLimits
+	elements int
+	size     int
 }
 
+// Read a data element. Errors are a *ReadError.
 func (d *DataValueReader) Read() (dataElement DataElement, err error) {
-	dataElementType, err := d.Reader.ReadByte()
+	d.limits = d.Limits
+	if d.limits.MaxDepth <= 0 {
+		d.limits.MaxDepth = DefaultReadLimits.MaxDepth
+	}
+
+	if d.limits.MaxElements <= 0 {
+		d.limits.MaxElements = DefaultReadLimits.MaxElements
+	}
+
+	if d.limits.MaxSize <= 0 {
+		d.limits.MaxSize = DefaultReadLimits.MaxSize
+	}
+
+	d.elements = 0
+	d.size = 0
+	return d.readElement(0)
+}
+
+func (d *DataValueReader) offset() int64 {
+	return d.Reader.Size() - int64(d.Reader.Len())
+}
+
+func (d *DataValueReader) fail(offset int64, err error) error {
+	return &ReadError{Offset: offset, Err: err}
+}
+
+func (d *DataValueReader) readElement(depth int) (dataElement DataElement, err error) {
+	offset := d.offset()
+	if depth >= d.limits.MaxDepth {
+		err = d.fail(offset, ErrMaxDepth)
+		return
+	}
+
+	d.elements++
+	if d.elements > d.limits.MaxElements {
+		err = d.fail(offset, ErrMaxElements)
+		return
+	}
+
+	dataElementType, err := d.readByte()
 	if err != nil {
 		return
 	}
@@ -98,15 +184,15 @@ func (d *DataValueReader) Read() (dataElement DataElement, err error) {
 		dataElement = DataElement{Value: value}
 	case byte(DataElementArray): // Array
 		var size byte
-		size, err = d.Reader.ReadByte()
+		size, err = d.readByte()
 		if err != nil {
 			return
 		}
 
-		var values []DataElement
+		values := make([]DataElement, 0, size)
 		for i := 0; i < int(size); i++ {
 			var value DataElement
-			value, err = d.Read()
+			value, err = d.readElement(depth + 1)
 			if err != nil {
 				return
 			}
@@ -117,39 +203,62 @@ func (d *DataValueReader) Read() (dataElement DataElement, err error) {
 		dataElement = DataElement{Array: values}
 	case byte(DataElementFields): // Fields / Map
 		var size byte
-		size, err = d.Reader.ReadByte()
+		size, err = d.readByte()
 		if err != nil {
 			return
 		}
 
-		fields := make(map[DataValue]DataElement, 0)
+		fields := make(map[DataValue]DataElement, size)
+		keys := make([]DataValue, 0, size)
 		for i := 0; i < int(size); i++ {
+			keyOffset := d.offset()
+
 			var key DataValue
 			key, err = d.readDataValue()
 			if err != nil {
 				return
 			}
 
+			if _, ok := key.(big.Int); ok {
+				err = d.fail(keyOffset, ErrUnsupportedKey)
+				return
+			}
+
 			var value DataElement
-			value, err = d.Read()
+			value, err = d.readElement(depth + 1)
 			if err != nil {
 				return
 			}
 
+			// like the node, a duplicated key keeps its first position and its last value
+			if _, ok := fields[key]; !ok {
+				keys = append(keys, key)
+			}
+
 			fields[key] = value
 		}
 
-		dataElement = DataElement{Fields: fields}
+		dataElement = DataElement{Fields: fields, Keys: keys}
 	default:
-		err = fmt.Errorf("invalid data type")
+		err = d.fail(offset, ErrInvalidElementType)
 		return
 	}
 
 	return
 }
 
+func (d *DataValueReader) readByte() (value byte, err error) {
+	data, err := d.readBytes(1)
+	if err != nil {
+		return
+	}
+
+	value = data[0]
+	return
+}
+
 func (d *DataValueReader) readValueType() (valueType ValueType, err error) {
-	data, err := d.Reader.ReadByte()
+	data, err := d.readByte()
 	if err != nil {
 		return
 	}
@@ -159,7 +268,8 @@ func (d *DataValueReader) readValueType() (valueType ValueType, err error) {
 }
 
 func (d *DataValueReader) readBool() (value bool, err error) {
-	data, err := d.Reader.ReadByte()
+	offset := d.offset()
+	data, err := d.readByte()
 	if err != nil {
 		return
 	}
@@ -169,19 +279,20 @@ func (d *DataValueReader) readBool() (value bool, err error) {
 		value = false
 	case 1:
 		value = true
+	default:
+		err = d.fail(offset, ErrInvalidBool)
 	}
 
 	return
 }
 
 func (d *DataValueReader) readString() (value string, err error) {
-	size, err := d.Reader.ReadByte()
+	size, err := d.readByte()
 	if err != nil {
 		return
 	}
 
-	data := make([]byte, size)
-	_, err = d.Reader.Read(data)
+	data, err := d.readBytes(int(size))
 	if err != nil {
 		return
 	}
@@ -190,19 +301,32 @@ func (d *DataValueReader) readString() (value string, err error) {
 	return
 }
 
+// Read exactly size bytes, within the size limit.
 func (d *DataValueReader) readBytes(size int) (value []byte, err error) {
+	offset := d.offset()
+	if d.size+size > d.limits.MaxSize {
+		err = d.fail(offset, ErrMaxSize)
+		return
+	}
+
 	data := make([]byte, size)
-	_, err = d.Reader.Read(data)
+	_, err = io.ReadFull(d.Reader, data)
 	if err != nil {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+
+		err = d.fail(offset, err)
 		return
 	}
 
+	d.size += size
 	value = data
 	return
 }
 
 func (d *DataValueReader) readU8() (value uint8, err error) {
-	data, err := d.Reader.ReadByte()
+	data, err := d.readByte()
 	if err != nil {
 		return
 	}
@@ -284,6 +408,8 @@ func (d *DataValueReader) readDataValue() (value DataValue, err error) {
 		value, err = d.readU128()
 	case HashType:
 		value, err = d.readHash()
+	default:
+		err = d.fail(d.offset()-1, ErrInvalidValueType)
 	}
 
 	return
@@ -312,6 +438,11 @@ func (d *DataValueWriter) Write(dataElement DataElement) (err error) {
 			return
 		}
 
+		if len(dataElement.Array) > 255 {
+			err = ErrMaxItemsSize
+			return
+		}
+
 		err = d.writeByte(byte(len(dataElement.Array)))
 		if err != nil {
 			return
@@ -331,18 +462,29 @@ func (d *DataValueWriter) Write(dataElement DataElement) (err error) {
 			return
 		}
 
+		if len(dataElement.Fields) > 255 {
+			err = ErrMaxItemsSize
+			return
+		}
+
 		err = d.writeByte(byte(len(dataElement.Fields)))
 		if err != nil {
 			return
 		}
 
-		for key, item := range dataElement.Fields {
-			err = d.writeValue(key)
+		var keys []fieldKey
+		keys, err = dataElement.fieldKeys()
+		if err != nil {
+			return
+		}
+
+		for _, key := range keys {
+			_, err = d.Writer.Write(key.data)
 			if err != nil {
 				return
 			}
 
-			err = d.Write(item)
+			err = d.Write(dataElement.Fields[key.value])
 			if err != nil {
 				return
 			}
@@ -352,6 +494,65 @@ func (d *DataValueWriter) Write(dataElement DataElement) (err error) {
 	return
 }
 
+type fieldKey struct {
+	value DataValue
+	data  []byte
+}
+
+// Keys of the fields with their encoding, in the order of Keys and then sorted by encoding.
+func (d DataElement) fieldKeys() (keys []fieldKey, err error) {
+	ordered := make(map[DataValue]bool, len(d.Keys))
+	for _, key := range d.Keys {
+		// checked before the map lookups, they panic on a big.Int key
+		if _, ok := key.(big.Int); ok {
+			err = ErrUnsupportedKey
+			return
+		}
+
+		var data []byte
+		data, err = encodeKey(key)
+		if err != nil {
+			return
+		}
+
+		if _, ok := d.Fields[key]; !ok || ordered[key] {
+			continue
+		}
+
+		ordered[key] = true
+		keys = append(keys, fieldKey{value: key, data: data})
+	}
+
+	var rest []fieldKey
+	for key := range d.Fields {
+		if ordered[key] {
+			continue
+		}
+
+		var data []byte
+		data, err = encodeKey(key)
+		if err != nil {
+			return
+		}
+
+		rest = append(rest, fieldKey{value: key, data: data})
+	}
+
+	sort.Slice(rest, func(i, j int) bool {
+		return bytes.Compare(rest[i].data, rest[j].data) < 0
+	})
+
+	keys = append(keys, rest...)
+	return
+}
+
+func encodeKey(key DataValue) ([]byte, error) {
+	var buf bytes.Buffer
+	writer := DataValueWriter{Writer: &buf}
+	err := writer.writeValue(key)
+	return buf.Bytes(), err
+}
+
 func (d *DataValueWriter) writeByte(value byte) (err error) {
 	data := make([]byte, 1)
 	data[0] = value
@@ -425,7 +626,14 @@ func (d *DataValueWriter) writeU64(value uint64) (err error) {
 }
 
 func (d *DataValueWriter) writeU128(value big.Int) (err error) {
-	_, err = d.Writer.Write(value.Bytes())
+	if value.Sign() < 0 || value.BitLen() > 128 {
+		err = ErrUnsupportedValue(value.String())
+		return
+	}
+
+	data := make([]byte, 16)
+	value.FillBytes(data)
+	_, err = d.Writer.Write(data)
 	return
 }
 
@@ -4,7 +4,10 @@ import (
 	"bytes"
 	"crypto/sha256"
 	"encoding/json"
+	"errors"
+	"io"
 	"math/big"
+	"reflect"
 	"testing"
 )
 
@@ -234,6 +237,181 @@ func TestDataElementFields(t *testing.T) {
 	}
 }
 
+func TestDataElementFieldsOrder(t *testing.T) {
+	fields := make(map[DataValue]DataElement, 0)
+	fields[uint8(7)] = DataElement{Value: uint8(8)}
+	fields["aa"] = DataElement{Value: true}
+	fields["b"] = DataElement{Value: "w"}
+	fields[true] = DataElement{Value: false}
+
+	// without Keys, they are sorted by their encoding: type first, then value
+	expected := []byte{
+		2, 4,
+		0, 1, 0, 0, 0,
+		1, 1, 'b', 0, 1, 1, 'w',
+		1, 2, 'a', 'a', 0, 0, 1,
+		2, 7, 0, 2, 8,
+	}
+
+	for i := 0; i < 20; i++ {
+		data, err := writeDataElement(DataElement{Fields: fields})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if !bytes.Equal(data, expected) {
+			t.Fatalf("Expected %v, got %v", expected, data)
+		}
+	}
+}
+
+func TestDataElementKeysOrder(t *testing.T) {
+	fields := make(map[DataValue]DataElement, 0)
+	fields[uint8(7)] = DataElement{Value: uint8(8)}
+	fields["aa"] = DataElement{Value: true}
+	fields["b"] = DataElement{Value: "w"}
+	fields[true] = DataElement{Value: false}
+
+	// keys in Keys first, unknown and duplicated ones are ignored, then the others sorted
+	element := DataElement{Fields: fields, Keys: []DataValue{uint8(7), "missing", "aa", uint8(7)}}
+	expected := []byte{
+		2, 4,
+		2, 7, 0, 2, 8,
+		1, 2, 'a', 'a', 0, 0, 1,
+		0, 1, 0, 0, 0,
+		1, 1, 'b', 0, 1, 1, 'w',
+	}
+
+	data, err := writeDataElement(element)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.Equal(data, expected) {
+		t.Fatalf("Expected %v, got %v", expected, data)
+	}
+
+	// the order is read back
+	reader := DataValueReader{Reader: bytes.NewReader(data)}
+	read, err := reader.Read()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectedKeys := []DataValue{uint8(7), "aa", true, "b"}
+	if !reflect.DeepEqual(read.Keys, expectedKeys) {
+		t.Errorf("Expected keys %v, got %v", expectedKeys, read.Keys)
+	}
+
+	// u128 values can't be keys, like in the reader
+	element.Keys = append(element.Keys, *big.NewInt(1))
+	_, err = writeDataElement(element)
+	if !errors.Is(err, ErrUnsupportedKey) {
+		t.Errorf("Expected unsupported key error, got %v", err)
+	}
+
+	element.Keys = []DataValue{[]byte{1}}
+	_, err = writeDataElement(element)
+	if err == nil {
+		t.Error("Expected unsupported value error")
+	}
+}
+
+func TestDataValueReaderErrors(t *testing.T) {
+	nested := bytes.Repeat([]byte{1, 1}, 40)
+	nested = append(nested, 0, 0, 1)
+
+	tests := []struct {
+		name   string
+		data   []byte
+		limits ReadLimits
+		err    error
+		offset int64
+	}{
+		{"depth", nested, ReadLimits{}, ErrMaxDepth, 64},
+		{"elements", []byte{1, 3, 0, 2, 1, 0, 2, 2, 0, 2, 3}, ReadLimits{MaxElements: 3}, ErrMaxElements, 8},
+		{"size", []byte{0, 1, 5, 'h', 'e', 'l', 'l', 'o'}, ReadLimits{MaxSize: 4}, ErrMaxSize, 3},
+		{"truncated", []byte{0, 5, 0, 0, 0}, ReadLimits{}, io.ErrUnexpectedEOF, 2},
+		{"empty", []byte{}, ReadLimits{}, io.ErrUnexpectedEOF, 0},
+		{"element type", []byte{1, 1, 3}, ReadLimits{}, ErrInvalidElementType, 2},
+		{"value type", []byte{0, 8}, ReadLimits{}, ErrInvalidValueType, 1},
+		{"bool", []byte{0, 0, 2}, ReadLimits{}, ErrInvalidBool, 2},
+		{"u128 key", append([]byte{2, 1, 6}, make([]byte, 19)...), ReadLimits{}, ErrUnsupportedKey, 2},
+	}
+
+	for _, test := range tests {
+		reader := DataValueReader{Reader: bytes.NewReader(test.data), Limits: test.limits}
+		_, err := reader.Read()
+
+		var readErr *ReadError
+		if !errors.As(err, &readErr) || !errors.Is(err, test.err) || readErr.Offset != test.offset {
+			t.Errorf("%s: expected %s at offset %d, got %v", test.name, test.err, test.offset, err)
+		}
+	}
+}
+
+func TestDataElementEmptyArray(t *testing.T) {
+	data, err := writeDataElement(DataElement{Array: []DataElement{}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dataElement, err := readDataElement(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if dataElement.Array == nil || len(dataElement.Array) != 0 {
+		t.Errorf("Expected an empty array, got %+v", dataElement)
+	}
+}
+
+func FuzzDataValueReader(f *testing.F) {
+	fields := make(map[DataValue]DataElement, 0)
+	fields["hello"] = DataElement{Value: "world"}
+	fields[uint64(1)] = DataElement{Array: []DataElement{{Value: true}, {Value: *big.NewInt(10)}, {Value: Hash{}}}}
+
+	for _, dataElement := range []DataElement{{Fields: fields}, {Value: uint16(5)}, {Array: []DataElement{}}} {
+		data, err := writeDataElement(dataElement)
+		if err != nil {
+			f.Fatal(err)
+		}
+
+		f.Add(data)
+	}
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		dataElement, err := readDataElement(data)
+		if err != nil {
+			var readErr *ReadError
+			if !errors.As(err, &readErr) {
+				t.Fatalf("Expected a *ReadError, got %v", err)
+			}
+			return
+		}
+
+		// duplicated keys are merged, so only the encoding of the decoded element is stable
+		encoded, err := writeDataElement(dataElement)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		dataElement, err = readDataElement(encoded)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		encodedCopy, err := writeDataElement(dataElement)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if !bytes.Equal(encoded, encodedCopy) {
+			t.Errorf("Expected %x, got %x", encoded, encodedCopy)
+		}
+	})
+}
+
 func TestLongStringMaxLimit(t *testing.T) {
 	// max 255 bytes for string
 	_, err := writeDataElement(DataElement{Value: "woenrbowirentboiejwrntbpoijewnrtbpenrptbjnepritjbnperijtnbpijewnrtbpjnerptbnjperkjtbnperkjtnbpsdfgsergwngio453gn45oign345iogjnwosiwejrngwpo34i5ny3[45oyhi3n4p5[hokn3p4o5nhekjrntbpkjewnrtpbkjnwerptkbjnpwkrjntbperkjntbpkwerntpbjkenrptbkjnwpekjnrwkpenrfpbknweprkbjnwperkbjn"})
@@ -295,3 +473,40 @@ func TestToMap(t *testing.T) {
 	t.Logf("%+v", sMap)
 	t.Logf("%+v", string(jsonString))
 }
+
+func TestWriteU128(t *testing.T) {
+	// always 16 bytes big endian, as read back by readU128
+	data, err := writeDataElement(DataElement{Value: *big.NewInt(258)})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []byte{0, 6, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 1, 2}
+	if !bytes.Equal(data, expected) {
+		t.Errorf("Expected %v, got %v", expected, data)
+	}
+
+	for _, value := range []*big.Int{big.NewInt(-1), new(big.Int).Lsh(big.NewInt(1), 128)} {
+		_, err = writeDataElement(DataElement{Value: *value})
+		if err == nil {
+			t.Errorf("Expected an error for %s", value)
+		}
+	}
+}
+
+func TestMaxItemsSize(t *testing.T) {
+	_, err := writeDataElement(DataElement{Array: make([]DataElement, 256)})
+	if err != ErrMaxItemsSize {
+		t.Errorf("Expected %s, got %v", ErrMaxItemsSize, err)
+	}
+
+	fields := make(map[DataValue]DataElement, 0)
+	for i := 0; i < 256; i++ {
+		fields[uint16(i)] = DataElement{Value: true}
+	}
+
+	_, err = writeDataElement(DataElement{Fields: fields})
+	if err != ErrMaxItemsSize {
+		t.Errorf("Expected %s, got %v", ErrMaxItemsSize, err)
+	}
+}
@@ -0,0 +1,328 @@
+package address
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/big"
+	"reflect"
+	"strings"
+)
+
+// Marshal and Unmarshal map Go values to data elements:
+//
+//	bool, string, uint8, uint16, uint32 and uint64 are values of the same type, uint is a u64
+//	*big.Int and big.Int are u128 values, Hash is a hash value
+//	slices and arrays are arrays, a []byte is an array of u8 values
+//	structs are fields keyed by the name in their xelis tag, or the field name, in the order of the struct
+//
+// Use `xelis:"name"` to rename a field and `xelis:"-"` to skip it. Unexported fields are skipped.
+// A nil pointer field is left out, the binary format has no null value.
+// Signed integers, floats, maps, interfaces and values over the size limits of the format are rejected.
+
+var ErrNotPointer = errors.New("unmarshal target must be a non-nil pointer")
+
+func ErrUnsupportedType(t reflect.Type) error {
+	return fmt.Errorf("unsupported type %s", t)
+}
+
+func ErrInvalidType(value DataValue, t reflect.Type) error {
+	return fmt.Errorf("cannot unmarshal %v (%T) into %s", value, value, t)
+}
+
+var bigIntType = reflect.TypeOf(big.Int{})
+var hashType = reflect.TypeOf(Hash{})
+
+func Marshal(v any) (DataElement, error) {
+	value := reflect.ValueOf(v)
+	if !value.IsValid() {
+		return DataElement{}, ErrUnsupportedValue(v)
+	}
+
+	return marshalValue(value)
+}
+
+func marshalValue(value reflect.Value) (element DataElement, err error) {
+	for value.Kind() == reflect.Pointer {
+		if value.IsNil() {
+			err = ErrUnsupportedValue(nil)
+			return
+		}
+
+		value = value.Elem()
+	}
+
+	switch value.Type() {
+	case bigIntType:
+		number := value.Interface().(big.Int)
+		if number.Sign() < 0 || number.BitLen() > 128 {
+			err = ErrUnsupportedValue(number.String())
+			return
+		}
+
+		// don't share the words of the marshalled value
+		element.Value = *new(big.Int).Set(&number)
+		return
+	case hashType:
+		element.Value = value.Interface().(Hash)
+		return
+	}
+
+	switch value.Kind() {
+	case reflect.Bool:
+		element.Value = value.Bool()
+	case reflect.String:
+		if len(value.String()) > 255 {
+			err = ErrMaxStringSize
+			return
+		}
+
+		element.Value = value.String()
+	case reflect.Uint8:
+		element.Value = uint8(value.Uint())
+	case reflect.Uint16:
+		element.Value = uint16(value.Uint())
+	case reflect.Uint32:
+		element.Value = uint32(value.Uint())
+	case reflect.Uint64, reflect.Uint:
+		element.Value = value.Uint()
+	case reflect.Slice, reflect.Array:
+		if value.Len() > 255 {
+			err = ErrMaxItemsSize
+			return
+		}
+
+		element.Array = make([]DataElement, 0, value.Len())
+		for i := 0; i < value.Len(); i++ {
+			var item DataElement
+			item, err = marshalValue(value.Index(i))
+			if err != nil {
+				return
+			}
+
+			element.Array = append(element.Array, item)
+		}
+	case reflect.Struct:
+		element.Fields = make(map[DataValue]DataElement)
+		element.Keys = make([]DataValue, 0)
+		for _, field := range structFields(value.Type()) {
+			fieldValue := value.Field(field.index)
+			if fieldValue.Kind() == reflect.Pointer && fieldValue.IsNil() {
+				continue
+			}
+
+			var item DataElement
+			item, err = marshalValue(fieldValue)
+			if err != nil {
+				err = fmt.Errorf("field %s: %w", field.name, err)
+				return
+			}
+
+			element.Fields[field.name] = item
+			element.Keys = append(element.Keys, field.name)
+		}
+
+		if len(element.Fields) > 255 {
+			err = ErrMaxItemsSize
+			return
+		}
+	default:
+		err = ErrUnsupportedType(value.Type())
+	}
+
+	return
+}
+
+type structField struct {
+	name  string
+	index int
+}
+
+func structFields(t reflect.Type) (fields []structField) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		name := field.Name
+		tag, ok := field.Tag.Lookup("xelis")
+		if ok {
+			tag, _, _ = strings.Cut(tag, ",")
+			if tag == "-" {
+				continue
+			}
+
+			if tag != "" {
+				name = tag
+			}
+		}
+
+		fields = append(fields, structField{name: name, index: i})
+	}
+
+	return
+}
+
+// Fill the value pointed by v with the data element.
+// Numbers can be read into any unsigned type holding them, and hashes from hex strings,
+// as data elements decoded from JSON use the smallest number type and strings for hashes.
+// Fields missing from the element are left untouched and unknown ones are ignored.
+func Unmarshal(element DataElement, v any) error {
+	value := reflect.ValueOf(v)
+	if value.Kind() != reflect.Pointer || value.IsNil() {
+		return ErrNotPointer
+	}
+
+	return unmarshalValue(element, value.Elem())
+}
+
+func unmarshalValue(element DataElement, value reflect.Value) (err error) {
+	if value.Kind() == reflect.Pointer {
+		if !value.IsNil() {
+			return unmarshalValue(element, value.Elem())
+		}
+
+		// only set once the element is known to fit, a nil pointer stays nil on error
+		target := reflect.New(value.Type().Elem())
+		err = unmarshalValue(element, target.Elem())
+		if err != nil {
+			return
+		}
+
+		value.Set(target)
+		return
+	}
+
+	switch value.Type() {
+	case bigIntType:
+		var number *big.Int
+		number, err = unmarshalBig(element.Value, value.Type())
+		if err != nil {
+			return
+		}
+
+		value.Set(reflect.ValueOf(*number))
+		return
+	case hashType:
+		var hash Hash
+		hash, err = unmarshalHash(element.Value, value.Type())
+		if err != nil {
+			return
+		}
+
+		value.Set(reflect.ValueOf(hash))
+		return
+	}
+
+	switch value.Kind() {
+	case reflect.Bool:
+		b, ok := element.Value.(bool)
+		if !ok {
+			return ErrInvalidType(element.Value, value.Type())
+		}
+
+		value.SetBool(b)
+	case reflect.String:
+		s, ok := element.Value.(string)
+		if !ok {
+			return ErrInvalidType(element.Value, value.Type())
+		}
+
+		value.SetString(s)
+	case reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uint:
+		var number *big.Int
+		number, err = unmarshalBig(element.Value, value.Type())
+		if err != nil {
+			return
+		}
+
+		if !number.IsUint64() || value.OverflowUint(number.Uint64()) {
+			return ErrInvalidType(element.Value, value.Type())
+		}
+
+		value.SetUint(number.Uint64())
+	case reflect.Slice:
+		if element.Array == nil {
+			return ErrInvalidType(element.Value, value.Type())
+		}
+
+		slice := reflect.MakeSlice(value.Type(), len(element.Array), len(element.Array))
+		for i, item := range element.Array {
+			err = unmarshalValue(item, slice.Index(i))
+			if err != nil {
+				return
+			}
+		}
+
+		value.Set(slice)
+	case reflect.Array:
+		if element.Array == nil || len(element.Array) != value.Len() {
+			return ErrInvalidType(element.Value, value.Type())
+		}
+
+		for i, item := range element.Array {
+			err = unmarshalValue(item, value.Index(i))
+			if err != nil {
+				return
+			}
+		}
+	case reflect.Struct:
+		if element.Fields == nil {
+			return ErrInvalidType(element.Value, value.Type())
+		}
+
+		for _, field := range structFields(value.Type()) {
+			item, ok := element.Fields[field.name]
+			if !ok {
+				continue
+			}
+
+			err = unmarshalValue(item, value.Field(field.index))
+			if err != nil {
+				return fmt.Errorf("field %s: %w", field.name, err)
+			}
+		}
+	default:
+		err = ErrUnsupportedType(value.Type())
+	}
+
+	return
+}
+
+func unmarshalBig(value DataValue, t reflect.Type) (*big.Int, error) {
+	switch value := value.(type) {
+	case uint8:
+		return new(big.Int).SetUint64(uint64(value)), nil
+	case uint16:
+		return new(big.Int).SetUint64(uint64(value)), nil
+	case uint32:
+		return new(big.Int).SetUint64(uint64(value)), nil
+	case uint64:
+		return new(big.Int).SetUint64(value), nil
+	case big.Int:
+		return new(big.Int).Set(&value), nil
+	default:
+		return nil, ErrInvalidType(value, t)
+	}
+}
+
+func unmarshalHash(value DataValue, t reflect.Type) (hash Hash, err error) {
+	switch value := value.(type) {
+	case Hash:
+		hash = value
+	case string:
+		var data []byte
+		data, err = hex.DecodeString(value)
+		if err != nil || len(data) != len(hash) {
+			err = ErrInvalidType(value, t)
+			return
+		}
+
+		copy(hash[:], data)
+	default:
+		err = ErrInvalidType(value, t)
+	}
+
+	return
+}
@@ -0,0 +1,159 @@
+package address
+
+import (
+	"encoding/json"
+	"math/big"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type invoiceItem struct {
+	Name     string `xelis:"name"`
+	Quantity uint16 `xelis:"qty"`
+}
+
+type invoice struct {
+	ID       uint64        `xelis:"id"`
+	Paid     bool          `xelis:"paid"`
+	Total    *big.Int      `xelis:"total"`
+	Asset    Hash          `xelis:"asset"`
+	Items    []invoiceItem `xelis:"items"`
+	Memo     *string       `xelis:"memo"`
+	Internal string        `xelis:"-"`
+	Version  uint8
+	secret   string
+}
+
+func TestMarshal(t *testing.T) {
+	var asset Hash
+	asset[31] = 1
+
+	total, _ := new(big.Int).SetString("100000000000000000000000", 10)
+	value := invoice{
+		ID:       42,
+		Paid:     true,
+		Total:    total,
+		Asset:    asset,
+		Items:    []invoiceItem{{Name: "coffee", Quantity: 2}, {Name: "cake", Quantity: 1}},
+		Internal: "skipped",
+		Version:  1,
+		secret:   "skipped",
+	}
+
+	element, err := Marshal(&value)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	fields := element.Fields
+	if len(fields) != 6 || fields["id"].Value != uint64(42) || fields["paid"].Value != true || fields["Version"].Value != uint8(1) {
+		t.Errorf("Unexpected fields %+v", fields)
+	}
+
+	if _, ok := fields["memo"]; ok {
+		t.Error("Expected nil memo to be left out")
+	}
+
+	if items := fields["items"].Array; len(items) != 2 || items[1].Fields["qty"].Value != uint16(1) {
+		t.Errorf("Unexpected items %+v", fields["items"])
+	}
+
+	// through the binary format
+	data, err := writeDataElement(element)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	element, err = readDataElement(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var valueCopy invoice
+	err = Unmarshal(element, &valueCopy)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	value.Internal = ""
+	value.secret = ""
+	if !reflect.DeepEqual(value, valueCopy) {
+		t.Errorf("Expected %+v, got %+v", value, valueCopy)
+	}
+}
+
+func TestUnmarshalJSON(t *testing.T) {
+	// numbers use the smallest type and hashes are strings once decoded from JSON
+	data := `{"id":42,"paid":false,"total":5,"asset":"0000000000000000000000000000000000000000000000000000000000000001","items":[{"name":"tea","qty":300}],"memo":"thanks","unknown":true}`
+
+	var element DataElement
+	err := json.Unmarshal([]byte(data), &element)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var value invoice
+	err = Unmarshal(element, &value)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if value.ID != 42 || value.Total.Int64() != 5 || value.Asset[31] != 1 || value.Items[0].Quantity != 300 || *value.Memo != "thanks" {
+		t.Errorf("Unexpected value %+v", value)
+	}
+}
+
+func TestMarshalUnsupported(t *testing.T) {
+	values := []interface{}{
+		nil,
+		int(1),
+		1.5,
+		map[string]string{"a": "b"},
+		struct{ Value int64 }{},
+		strings.Repeat("a", 256),
+		make([]uint8, 256),
+		new(big.Int).Neg(big.NewInt(1)),
+		new(big.Int).Lsh(big.NewInt(1), 128),
+	}
+
+	for _, value := range values {
+		_, err := Marshal(value)
+		if err == nil {
+			t.Errorf("Expected an error for %v", value)
+		}
+	}
+}
+
+func TestUnmarshalInvalid(t *testing.T) {
+	var small struct {
+		Value uint8 `xelis:"value"`
+	}
+
+	fields := map[DataValue]DataElement{"value": {Value: uint16(256)}}
+	err := Unmarshal(DataElement{Fields: fields}, &small)
+	if err == nil {
+		t.Error("Expected an overflow error")
+	}
+
+	var s string
+	err = Unmarshal(DataElement{Value: true}, &s)
+	if err == nil {
+		t.Error("Expected a type error")
+	}
+
+	err = Unmarshal(DataElement{Value: "a"}, s)
+	if err != ErrNotPointer {
+		t.Errorf("Expected %s, got %v", ErrNotPointer, err)
+	}
+
+	var memo struct {
+		Memo *string `xelis:"memo"`
+	}
+
+	fields = map[DataValue]DataElement{"memo": {Value: uint8(1)}}
+	err = Unmarshal(DataElement{Fields: fields}, &memo)
+	if err == nil || memo.Memo != nil {
+		t.Errorf("Expected a type error and a nil pointer, got %v and %v", err, memo.Memo)
+	}
+}
@@ -0,0 +1,250 @@
+package block
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/xelis-project/xelis-go-sdk/address"
+	"github.com/xelis-project/xelis-go-sdk/daemon"
+	"github.com/zeebo/blake3"
+)
+
+const EXTRA_NONCE_SIZE = 32
+
+type Hash [32]byte
+
+func (h Hash) String() string {
+	return hex.EncodeToString(h[:])
+}
+
+func NewHashFromString(value string) (hash Hash, err error) {
+	data, err := hex.DecodeString(value)
+	if err != nil {
+		return
+	}
+
+	if len(data) != len(hash) {
+		err = fmt.Errorf("invalid hash size %d", len(data))
+		return
+	}
+
+	copy(hash[:], data)
+	return
+}
+
+var ErrTrailingBytes = errors.New("unexpected bytes after the block header")
+
+type Header struct {
+	Version    uint8
+	Height     uint64
+	Timestamp  uint64 // in milliseconds
+	Nonce      uint64
+	ExtraNonce [EXTRA_NONCE_SIZE]byte
+	Tips       []Hash
+	TxsHashes  []Hash
+	Miner      [32]byte // compressed public key
+}
+
+// Decode a block header, like the template of get_block_template once hex decoded.
+func DecodeHeader(data []byte) (header Header, err error) {
+	reader := bytes.NewReader(data)
+	read := func(value interface{}) {
+		if err == nil {
+			err = binary.Read(reader, binary.BigEndian, value)
+		}
+	}
+
+	read(&header.Version)
+	read(&header.Height)
+	read(&header.Timestamp)
+	read(&header.Nonce)
+	read(&header.ExtraNonce)
+
+	var tips uint8
+	read(&tips)
+	if err == nil {
+		header.Tips = make([]Hash, tips)
+		read(header.Tips)
+	}
+
+	var txs uint16
+	read(&txs)
+	if err == nil {
+		if int(txs)*32 > reader.Len() {
+			err = io.ErrUnexpectedEOF
+			return
+		}
+
+		header.TxsHashes = make([]Hash, txs)
+		read(header.TxsHashes)
+	}
+
+	read(&header.Miner)
+	if err == io.EOF {
+		err = io.ErrUnexpectedEOF
+	}
+
+	if err == nil && reader.Len() > 0 {
+		err = ErrTrailingBytes
+	}
+
+	return
+}
+
+func DecodeHeaderHex(value string) (header Header, err error) {
+	data, err := hex.DecodeString(value)
+	if err != nil {
+		return
+	}
+
+	return DecodeHeader(data)
+}
+
+// Header of a block returned by the daemon.
+func NewHeaderFromBlock(block daemon.Block) (header Header, err error) {
+	if block.Version > 0xFF {
+		err = fmt.Errorf("invalid block version %d", block.Version)
+		return
+	}
+
+	header = Header{
+		Version:   uint8(block.Version),
+		Height:    block.Height,
+		Timestamp: block.Timestamp,
+		Nonce:     block.Nonce,
+	}
+
+	extraNonce, err := NewHashFromString(block.ExtraNonce)
+	if err != nil {
+		return
+	}
+	header.ExtraNonce = extraNonce
+
+	for _, tip := range block.Tips {
+		var hash Hash
+		hash, err = NewHashFromString(tip)
+		if err != nil {
+			return
+		}
+
+		header.Tips = append(header.Tips, hash)
+	}
+
+	for _, txHash := range block.TxsHashes {
+		var hash Hash
+		hash, err = NewHashFromString(txHash)
+		if err != nil {
+			return
+		}
+
+		header.TxsHashes = append(header.TxsHashes, hash)
+	}
+
+	miner, err := address.NewAddressFromString(block.Miner)
+	if err != nil {
+		return
+	}
+
+	if miner == nil {
+		err = fmt.Errorf("invalid miner address %s", block.Miner)
+		return
+	}
+
+	copy(header.Miner[:], miner.GetPublicKey())
+	return
+}
+
+func (h *Header) Encode() (data []byte, err error) {
+	if len(h.Tips) > 0xFF {
+		err = fmt.Errorf("%d tips is above the max limit of 255", len(h.Tips))
+		return
+	}
+
+	if len(h.TxsHashes) > 0xFFFF {
+		err = fmt.Errorf("%d txs hashes is above the max limit of 65535", len(h.TxsHashes))
+		return
+	}
+
+	buf := new(bytes.Buffer)
+	for _, value := range []interface{}{
+		h.Version, h.Height, h.Timestamp, h.Nonce, h.ExtraNonce,
+		uint8(len(h.Tips)), h.Tips,
+		uint16(len(h.TxsHashes)), h.TxsHashes,
+		h.Miner,
+	} {
+		binary.Write(buf, binary.BigEndian, value)
+	}
+
+	data = buf.Bytes()
+	return
+}
+
+func (h *Header) EncodeHex() (value string, err error) {
+	data, err := h.Encode()
+	if err != nil {
+		return
+	}
+
+	value = hex.EncodeToString(data)
+	return
+}
+
+func hashAll(hashes []Hash) Hash {
+	hasher := blake3.New()
+	for _, hash := range hashes {
+		hasher.Write(hash[:])
+	}
+
+	var result Hash
+	copy(result[:], hasher.Sum(nil))
+	return result
+}
+
+// Hash of the header fields not changed by miners: version, height, tips and txs hashes.
+func (h *Header) WorkHash() Hash {
+	tipsHash := hashAll(h.Tips)
+	txsHash := hashAll(h.TxsHashes)
+
+	data := make([]byte, 0, 1+8+32+32)
+	data = append(data, h.Version)
+	data = binary.BigEndian.AppendUint64(data, h.Height)
+	data = append(data, tipsHash[:]...)
+	data = append(data, txsHash[:]...)
+
+	return blake3.Sum256(data)
+}
+
+func (h *Header) MinerWork() MinerWork {
+	return MinerWork{
+		WorkHash:   h.WorkHash(),
+		Timestamp:  h.Timestamp,
+		Nonce:      h.Nonce,
+		ExtraNonce: h.ExtraNonce,
+		Miner:      h.Miner,
+	}
+}
+
+// Block hash, the hash of its miner work.
+func (h *Header) Hash() Hash {
+	work := h.MinerWork()
+	return work.Hash()
+}
+
+// Check that the hash of the block returned by the daemon matches its content.
+func VerifyBlockHash(block daemon.Block) error {
+	header, err := NewHeaderFromBlock(block)
+	if err != nil {
+		return err
+	}
+
+	hash := header.Hash()
+	if hash.String() != block.Hash {
+		return fmt.Errorf("block hash %s doesn't match its content hash %s", block.Hash, hash)
+	}
+
+	return nil
+}
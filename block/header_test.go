@@ -0,0 +1,235 @@
+package block
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"io"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/xelis-project/xelis-go-sdk/address"
+	"github.com/xelis-project/xelis-go-sdk/daemon"
+	"github.com/zeebo/blake3"
+)
+
+func testHeader() Header {
+	header := Header{
+		Version:   1,
+		Height:    1500,
+		Timestamp: 1696132639000,
+		Nonce:     42,
+		Tips:      []Hash{{1}, {2}},
+		TxsHashes: []Hash{{3}, {4}, {5}},
+	}
+	header.ExtraNonce[0] = 6
+	header.Miner[31] = 7
+	return header
+}
+
+func TestHeaderRoundTrip(t *testing.T) {
+	header := testHeader()
+	data, err := header.Encode()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	size := 1 + 8 + 8 + 8 + 32 + 1 + 2*32 + 2 + 3*32 + 32
+	if len(data) != size {
+		t.Fatalf("Expected %d bytes, got %d", size, len(data))
+	}
+
+	decoded, err := DecodeHeader(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(header, decoded) {
+		t.Errorf("Decoded header is different: %+v", decoded)
+	}
+
+	for _, size := range []int{0, 10, 57, 58, len(data) - 1} {
+		_, err = DecodeHeader(data[:size])
+		if !errors.Is(err, io.ErrUnexpectedEOF) {
+			t.Errorf("Expected unexpected EOF for %d bytes, got %v", size, err)
+		}
+	}
+
+	_, err = DecodeHeader(append(data, 0))
+	if !errors.Is(err, ErrTrailingBytes) {
+		t.Errorf("Expected trailing bytes error, got %v", err)
+	}
+}
+
+func TestMinerWork(t *testing.T) {
+	header := testHeader()
+	work := header.MinerWork()
+	data := work.Encode()
+	if len(data) != MINER_WORK_SIZE {
+		t.Fatalf("Expected %d bytes, got %d", MINER_WORK_SIZE, len(data))
+	}
+
+	decoded, err := DecodeMinerWorkHex(work.EncodeHex())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if decoded != work || !bytes.Equal(decoded.Encode(), data) {
+		t.Errorf("Decoded miner work is different: %+v", decoded)
+	}
+
+	if header.Hash() != work.Hash() {
+		t.Error("Header and miner work hashes are different")
+	}
+
+	// the work hash doesn't depend on the fields changed by miners
+	header.Nonce++
+	if header.WorkHash() != work.WorkHash || header.Hash() == work.Hash() {
+		t.Error("Unexpected hashes after changing the nonce")
+	}
+
+	header.Tips = header.Tips[:1]
+	if header.WorkHash() == work.WorkHash {
+		t.Error("Work hash must change with the tips")
+	}
+
+	_, err = DecodeMinerWork(data[1:])
+	if err == nil {
+		t.Error("Expected invalid size error")
+	}
+}
+
+// Hash of the block written out field by field, without the Header methods.
+func expectedBlockHash(t *testing.T, b daemon.Block) string {
+	hexBytes := func(value string) []byte {
+		data, err := hex.DecodeString(value)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return data
+	}
+
+	hashList := func(values []string) []byte {
+		var data []byte
+		for _, value := range values {
+			data = append(data, hexBytes(value)...)
+		}
+
+		hash := blake3.Sum256(data)
+		return hash[:]
+	}
+
+	miner, err := address.NewAddressFromString(b.Miner)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	work := []byte{byte(b.Version)}
+	work = binary.BigEndian.AppendUint64(work, b.Height)
+	work = append(work, hashList(b.Tips)...)
+	work = append(work, hashList(b.TxsHashes)...)
+	workHash := blake3.Sum256(work)
+
+	data := append([]byte{}, workHash[:]...)
+	data = binary.BigEndian.AppendUint64(data, b.Timestamp)
+	data = binary.BigEndian.AppendUint64(data, b.Nonce)
+	data = append(data, hexBytes(b.ExtraNonce)...)
+	data = append(data, miner.GetPublicKey()...)
+
+	hash := blake3.Sum256(data)
+	return hex.EncodeToString(hash[:])
+}
+
+func TestVerifyBlockHash(t *testing.T) {
+	header := testHeader()
+	b := daemon.Block{
+		Version:    1,
+		Height:     header.Height,
+		Timestamp:  header.Timestamp,
+		Nonce:      header.Nonce,
+		ExtraNonce: Hash(header.ExtraNonce).String(),
+		Miner:      "xet:62wnkswt0rmrdd9d2lawgpzuh87fkpmp4gx9j3g4u24yrdkdxgksqnuuucf",
+	}
+
+	for _, tip := range header.Tips {
+		b.Tips = append(b.Tips, tip.String())
+	}
+
+	for _, txHash := range header.TxsHashes {
+		b.TxsHashes = append(b.TxsHashes, txHash.String())
+	}
+
+	b.Hash = expectedBlockHash(t, b)
+	err := VerifyBlockHash(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// every field is part of the hash
+	changes := map[string]func(b *daemon.Block){
+		"version":     func(b *daemon.Block) { b.Version++ },
+		"height":      func(b *daemon.Block) { b.Height++ },
+		"timestamp":   func(b *daemon.Block) { b.Timestamp++ },
+		"nonce":       func(b *daemon.Block) { b.Nonce++ },
+		"extra nonce": func(b *daemon.Block) { b.ExtraNonce = Hash{9}.String() },
+		"tips order":  func(b *daemon.Block) { b.Tips = []string{b.Tips[1], b.Tips[0]} },
+		"txs":         func(b *daemon.Block) { b.TxsHashes = b.TxsHashes[:2] },
+		"miner":       func(b *daemon.Block) { b.Miner = "xet:ys4peuzztwl67rzhsdu0yxfzwcfmgt85uu53hycpeeary7n8qvysqmxznt0" },
+	}
+
+	for name, change := range changes {
+		changed := b
+		changed.Tips = append([]string{}, b.Tips...)
+		changed.TxsHashes = append([]string{}, b.TxsHashes...)
+		change(&changed)
+
+		err = VerifyBlockHash(changed)
+		if err == nil {
+			t.Errorf("%s: expected hash mismatch error", name)
+		}
+	}
+}
+
+func TestMinerWorkSetters(t *testing.T) {
+	header := testHeader()
+	work := header.MinerWork()
+
+	worker := work
+	worker.SetNonce(99)
+	worker.SetTimestamp(time.UnixMilli(1700000000000))
+	err := worker.SetExtraNonce([]byte{1, 2, 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	miner, err := address.NewAddressFromString("xet:62wnkswt0rmrdd9d2lawgpzuh87fkpmp4gx9j3g4u24yrdkdxgksqnuuucf")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = worker.SetMiner(miner)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if work.Nonce != 42 || work.ExtraNonce != header.ExtraNonce || work.Miner != header.Miner {
+		t.Error("Changing a copy must not change the original miner work")
+	}
+
+	decoded, err := DecodeMinerWorkHex(worker.EncodeHex())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if decoded.Nonce != 99 || decoded.Timestamp != 1700000000000 || decoded.ExtraNonce[2] != 3 || decoded.ExtraNonce[0] != 1 ||
+		!bytes.Equal(decoded.Miner[:], miner.GetPublicKey()) || decoded.WorkHash != work.WorkHash {
+		t.Errorf("Unexpected miner work %+v", decoded)
+	}
+
+	err = worker.SetExtraNonce(make([]byte, EXTRA_NONCE_SIZE+1))
+	if err == nil {
+		t.Error("Expected extra nonce size error")
+	}
+}
@@ -0,0 +1,97 @@
+package block
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"github.com/xelis-project/xelis-go-sdk/address"
+
+	"github.com/zeebo/blake3"
+)
+
+const MINER_WORK_SIZE = 32 + 8 + 8 + EXTRA_NONCE_SIZE + 32
+
+// Part of the block header hashed by miners, as in get_miner_work and the getwork template.
+// It only holds arrays, so a copy can be changed by a worker without affecting the others.
+type MinerWork struct {
+	WorkHash   Hash
+	Timestamp  uint64 // in milliseconds
+	Nonce      uint64
+	ExtraNonce [EXTRA_NONCE_SIZE]byte
+	Miner      [32]byte
+}
+
+func DecodeMinerWork(data []byte) (work MinerWork, err error) {
+	if len(data) != MINER_WORK_SIZE {
+		err = fmt.Errorf("invalid miner work size %d, expected %d", len(data), MINER_WORK_SIZE)
+		return
+	}
+
+	copy(work.WorkHash[:], data[0:32])
+	work.Timestamp = binary.BigEndian.Uint64(data[32:40])
+	work.Nonce = binary.BigEndian.Uint64(data[40:48])
+	copy(work.ExtraNonce[:], data[48:80])
+	copy(work.Miner[:], data[80:112])
+	return
+}
+
+func DecodeMinerWorkHex(value string) (work MinerWork, err error) {
+	data, err := hex.DecodeString(value)
+	if err != nil {
+		return
+	}
+
+	return DecodeMinerWork(data)
+}
+
+func (w *MinerWork) Encode() []byte {
+	data := make([]byte, 0, MINER_WORK_SIZE)
+	data = append(data, w.WorkHash[:]...)
+	data = binary.BigEndian.AppendUint64(data, w.Timestamp)
+	data = binary.BigEndian.AppendUint64(data, w.Nonce)
+	data = append(data, w.ExtraNonce[:]...)
+	data = append(data, w.Miner[:]...)
+	return data
+}
+
+func (w *MinerWork) EncodeHex() string {
+	return hex.EncodeToString(w.Encode())
+}
+
+// Block hash of the header this work comes from.
+func (w *MinerWork) Hash() Hash {
+	return blake3.Sum256(w.Encode())
+}
+
+func (w *MinerWork) SetNonce(nonce uint64) {
+	w.Nonce = nonce
+}
+
+func (w *MinerWork) SetTimestamp(timestamp time.Time) {
+	w.Timestamp = uint64(timestamp.UnixMilli())
+}
+
+// Extra nonce of at most EXTRA_NONCE_SIZE bytes. The remaining bytes are zeroed.
+// Give each worker its own extra nonce to split a job without overlapping nonces.
+func (w *MinerWork) SetExtraNonce(extraNonce []byte) error {
+	if len(extraNonce) > EXTRA_NONCE_SIZE {
+		return fmt.Errorf("extra nonce of %d bytes is above the max size of %d", len(extraNonce), EXTRA_NONCE_SIZE)
+	}
+
+	w.ExtraNonce = [EXTRA_NONCE_SIZE]byte{}
+	copy(w.ExtraNonce[:], extraNonce)
+	return nil
+}
+
+// Set the public key receiving the block reward.
+func (w *MinerWork) SetMiner(miner *address.Address) error {
+	publicKey := miner.GetPublicKey()
+	if len(publicKey) != len(w.Miner) {
+		return fmt.Errorf("invalid miner public key size %d", len(publicKey))
+	}
+
+	copy(w.Miner[:], publicKey)
+	return nil
+}
@@ -31,3 +31,9 @@ var LOCAL_XSWD_URL = fmt.Sprintf("127.0.0.1:%d", XSWD_PORT)
 var LOCAL_XSWD_WS = fmt.Sprintf("ws://%s/xswd", LOCAL_XSWD_URL)
 
 const XELIS_ASSET = `0000000000000000000000000000000000000000000000000000000000000000`
+
+// Number of blocks behind the top before a block is stable and can't be reordered anymore.
+const STABLE_LIMIT = 8
+
+// Maximum number of blocks returned by the daemon range methods.
+const MAX_BLOCKS_RANGE = 20
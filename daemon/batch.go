@@ -0,0 +1,167 @@
+package daemon
+
+import (
+	"context"
+
+	"github.com/creachadair/jrpc2"
+	"github.com/xelis-project/xelis-go-sdk/rpc"
+)
+
+// Batch queues daemon calls and sends them in a single JSON-RPC batch request.
+//
+//	batch := daemon.Batch()
+//	block := batch.GetBlockAtTopoheight(GetBlockAtTopoheightParams{Topoheight: 10})
+//	tx := batch.GetTransaction(hash)
+//	err := batch.Send()
+//
+// After Send, every queued call holds its own Result and Err.
+type Batch struct {
+	rpc     *RPC
+	specs   []jrpc2.Spec
+	results []func(rsp *jrpc2.Response, err error)
+}
+
+// Result of a call queued in a Batch. It is set once Send returns.
+type BatchCall[T any] struct {
+	Result T
+	Err    error
+}
+
+func (d *RPC) Batch() *Batch {
+	return &Batch{rpc: d}
+}
+
+// Queue any method with its params and the type of its result.
+// Use it for calls that don't have a Batch method.
+func Queue[T any](b *Batch, method string, params interface{}) *BatchCall[T] {
+	call := &BatchCall[T]{}
+	b.specs = append(b.specs, jrpc2.Spec{Method: method, Params: params})
+	b.results = append(b.results, func(rsp *jrpc2.Response, err error) {
+		if err != nil {
+			call.Err = err
+			return
+		}
+
+		call.Err = rpc.FromJRPC2Error(rsp.UnmarshalResult(&call.Result))
+	})
+
+	return call
+}
+
+// Number of queued calls.
+func (b *Batch) Len() int {
+	return len(b.specs)
+}
+
+func (b *Batch) Send() error {
+	return b.SendContext(b.rpc.ctx)
+}
+
+// Send every queued call in one request and set their results.
+// The returned error is only about sending the batch. In that case it is also set on every call.
+// The batch is reset and can be reused to queue new calls.
+func (b *Batch) SendContext(ctx context.Context) error {
+	specs, results := b.specs, b.results
+	b.specs, b.results = nil, nil
+
+	if len(specs) == 0 {
+		return nil
+	}
+
+	responses, err := b.rpc.Client.Batch(ctx, specs)
+	if err != nil {
+		err = rpc.FromJRPC2Error(err)
+		for _, result := range results {
+			result(nil, err)
+		}
+
+		return err
+	}
+
+	for i, result := range results {
+		result(responses[i], nil)
+	}
+
+	return nil
+}
+
+func (b *Batch) GetInfo() *BatchCall[GetInfoResult] {
+	return Queue[GetInfoResult](b, GetInfo, nil)
+}
+
+func (b *Batch) GetStableBalance(params GetBalanceParams) *BatchCall[GetStableBalanceResult] {
+	return Queue[GetStableBalanceResult](b, GetStableBalance, params)
+}
+
+func (b *Batch) GetBlockAtTopoheight(params GetBlockAtTopoheightParams) *BatchCall[Block] {
+	return Queue[Block](b, GetBlockAtTopoheight, params)
+}
+
+func (b *Batch) GetBlocksAtHeight(params GetBlocksAtHeightParams) *BatchCall[[]Block] {
+	return Queue[[]Block](b, GetBlocksAtHeight, params)
+}
+
+func (b *Batch) GetBlockByHash(params GetBlockByHashParams) *BatchCall[Block] {
+	return Queue[Block](b, GetBlockByHash, params)
+}
+
+func (b *Batch) GetNonce(addr string) *BatchCall[GetNonceResult] {
+	params := map[string]string{"address": addr}
+	return Queue[GetNonceResult](b, GetNonce, params)
+}
+
+func (b *Batch) GetNonceAtTopoheight(params GetNonceAtTopoheightParams) *BatchCall[VersionedNonce] {
+	return Queue[VersionedNonce](b, GetNonceAtTopoheight, params)
+}
+
+func (b *Batch) GetBalance(params GetBalanceParams) *BatchCall[GetBalanceResult] {
+	return Queue[GetBalanceResult](b, GetBalance, params)
+}
+
+func (b *Batch) GetBalanceAtTopoheight(params GetBalanceAtTopoheightParams) *BatchCall[VersionedBalance] {
+	return Queue[VersionedBalance](b, GetBalanceAtTopoheight, params)
+}
+
+func (b *Batch) GetAsset(assetId string) *BatchCall[Asset] {
+	params := map[string]string{"asset": assetId}
+	return Queue[Asset](b, GetAsset, params)
+}
+
+func (b *Batch) GetDAGOrder(params GetTopoheightRangeParams) *BatchCall[[]string] {
+	return Queue[[]string](b, GetDAGOrder, params)
+}
+
+func (b *Batch) GetTransaction(hash string) *BatchCall[Transaction] {
+	params := map[string]string{"hash": hash}
+	return Queue[Transaction](b, GetTransaction, params)
+}
+
+func (b *Batch) GetTransactions(params GetTransactionsParams) *BatchCall[[]Transaction] {
+	return Queue[[]Transaction](b, GetTransactions, params)
+}
+
+func (b *Batch) GetBlocksRangeByTopoheight(params GetTopoheightRangeParams) *BatchCall[[]Block] {
+	return Queue[[]Block](b, GetBlocksRangeByTopoheight, params)
+}
+
+func (b *Batch) GetBlocksRangeByHeight(params GetHeightRangeParams) *BatchCall[[]Block] {
+	return Queue[[]Block](b, GetBlocksRangeByHeight, params)
+}
+
+func (b *Batch) GetAccountHistory(addr string) *BatchCall[[]AccountHistory] {
+	params := map[string]string{"address": addr}
+	return Queue[[]AccountHistory](b, GetAccountHistory, params)
+}
+
+func (b *Batch) GetAccountAssets(addr string) *BatchCall[[]string] {
+	params := map[string]string{"address": addr}
+	return Queue[[]string](b, GetAccountAssets, params)
+}
+
+func (b *Batch) IsTxExecutedInBlock(params IsTxExecutedInBlockParams) *BatchCall[bool] {
+	return Queue[bool](b, IsTxExecutedInBlock, params)
+}
+
+func (b *Batch) IsAccountRegistered(params IsAccountRegisteredParams) *BatchCall[bool] {
+	return Queue[bool](b, IsAccountRegistered, params)
+}
@@ -0,0 +1,105 @@
+package daemon
+
+import "context"
+
+// Client is the set of daemon calls available on both RPC and WebSocket.
+// Use it to swap the transport or inject a fake in tests.
+type Client interface {
+	GetVersion() (version string, err error)
+	GetVersionContext(ctx context.Context) (version string, err error)
+	GetInfo() (result GetInfoResult, err error)
+	GetInfoContext(ctx context.Context) (result GetInfoResult, err error)
+	GetHeight() (height uint64, err error)
+	GetHeightContext(ctx context.Context) (height uint64, err error)
+	GetTopoheight() (topoheight uint64, err error)
+	GetTopoheightContext(ctx context.Context) (topoheight uint64, err error)
+	GetStableHeight() (stableheight uint64, err error)
+	GetStableHeightContext(ctx context.Context) (stableheight uint64, err error)
+	GetStableTopoheight() (topoheight uint64, err error)
+	GetStableTopoheightContext(ctx context.Context) (topoheight uint64, err error)
+	GetStableBalance(params GetBalanceParams) (result GetStableBalanceResult, err error)
+	GetStableBalanceContext(ctx context.Context, params GetBalanceParams) (result GetStableBalanceResult, err error)
+	GetBlockTemplate(addr string) (result GetBlockTemplateResult, err error)
+	GetBlockTemplateContext(ctx context.Context, addr string) (result GetBlockTemplateResult, err error)
+	GetBlockAtTopoheight(params GetBlockAtTopoheightParams) (block Block, err error)
+	GetBlockAtTopoheightContext(ctx context.Context, params GetBlockAtTopoheightParams) (block Block, err error)
+	GetBlocksAtHeight(params GetBlocksAtHeightParams) (blocks []Block, err error)
+	GetBlocksAtHeightContext(ctx context.Context, params GetBlocksAtHeightParams) (blocks []Block, err error)
+	GetBlockByHash(params GetBlockByHashParams) (block Block, err error)
+	GetBlockByHashContext(ctx context.Context, params GetBlockByHashParams) (block Block, err error)
+	GetTopBlock(params GetTopBlockParams) (block Block, err error)
+	GetTopBlockContext(ctx context.Context, params GetTopBlockParams) (block Block, err error)
+	GetNonce(addr string) (nonce GetNonceResult, err error)
+	GetNonceContext(ctx context.Context, addr string) (nonce GetNonceResult, err error)
+	HasNonce(addr string) (hasNonce bool, err error)
+	HasNonceContext(ctx context.Context, addr string) (hasNonce bool, err error)
+	GetNonceAtTopoheight(params GetNonceAtTopoheightParams) (nonce VersionedNonce, err error)
+	GetNonceAtTopoheightContext(ctx context.Context, params GetNonceAtTopoheightParams) (nonce VersionedNonce, err error)
+	GetBalance(params GetBalanceParams) (balance GetBalanceResult, err error)
+	GetBalanceContext(ctx context.Context, params GetBalanceParams) (balance GetBalanceResult, err error)
+	HasBalance(params GetBalanceParams) (hasBalance bool, err error)
+	HasBalanceContext(ctx context.Context, params GetBalanceParams) (hasBalance bool, err error)
+	GetBalanceAtTopoheight(params GetBalanceAtTopoheightParams) (balance VersionedBalance, err error)
+	GetBalanceAtTopoheightContext(ctx context.Context, params GetBalanceAtTopoheightParams) (balance VersionedBalance, err error)
+	GetAsset(assetId string) (asset Asset, err error)
+	GetAssetContext(ctx context.Context, assetId string) (asset Asset, err error)
+	GetAssets(params GetAssetsParams) (assets []AssetWithData, err error)
+	GetAssetsContext(ctx context.Context, params GetAssetsParams) (assets []AssetWithData, err error)
+	CountAssets() (count uint64, err error)
+	CountAssetsContext(ctx context.Context) (count uint64, err error)
+	CountTransactions() (count uint64, err error)
+	CountTransactionsContext(ctx context.Context) (count uint64, err error)
+	CountAccounts() (count uint64, err error)
+	CountAccountsContext(ctx context.Context) (count uint64, err error)
+	GetTips() (tips []string, err error)
+	GetTipsContext(ctx context.Context) (tips []string, err error)
+	P2PStatus() (status P2PStatusResult, err error)
+	P2PStatusContext(ctx context.Context) (status P2PStatusResult, err error)
+	GetDAGOrder(params GetTopoheightRangeParams) (hashes []string, err error)
+	GetDAGOrderContext(ctx context.Context, params GetTopoheightRangeParams) (hashes []string, err error)
+	SubmitBlock(params SubmitBlockParams) (result bool, err error)
+	SubmitBlockContext(ctx context.Context, params SubmitBlockParams) (result bool, err error)
+	SubmitTransaction(data string) (result bool, err error)
+	SubmitTransactionContext(ctx context.Context, data string) (result bool, err error)
+	GetMempool() (txs []Transaction, err error)
+	GetMempoolContext(ctx context.Context) (txs []Transaction, err error)
+	GetTransaction(hash string) (tx Transaction, err error)
+	GetTransactionContext(ctx context.Context, hash string) (tx Transaction, err error)
+	GetTransactions(params GetTransactionsParams) (txs []Transaction, err error)
+	GetTransactionsContext(ctx context.Context, params GetTransactionsParams) (txs []Transaction, err error)
+	GetBlocksRangeByTopoheight(params GetTopoheightRangeParams) (blocks []Block, err error)
+	GetBlocksRangeByTopoheightContext(ctx context.Context, params GetTopoheightRangeParams) (blocks []Block, err error)
+	GetBlocksRangeByHeight(params GetHeightRangeParams) (blocks []Block, err error)
+	GetBlocksRangeByHeightContext(ctx context.Context, params GetHeightRangeParams) (blocks []Block, err error)
+	GetAccounts(params GetAccountsParams) (addresses []string, err error)
+	GetAccountsContext(ctx context.Context, params GetAccountsParams) (addresses []string, err error)
+	GetAccountHistory(addr string) (history []AccountHistory, err error)
+	GetAccountHistoryContext(ctx context.Context, addr string) (history []AccountHistory, err error)
+	GetAccountAssets(addr string) (assets []string, err error)
+	GetAccountAssetsContext(ctx context.Context, addr string) (assets []string, err error)
+	GetPeers() (result GetPeersResult, err error)
+	GetPeersContext(ctx context.Context) (result GetPeersResult, err error)
+	GetDevFeeThresholds() (fees []Fee, err error)
+	GetDevFeeThresholdsContext(ctx context.Context) (fees []Fee, err error)
+	GetSizeOnDisk() (sizeOnDisk SizeOnDisk, err error)
+	GetSizeOnDiskContext(ctx context.Context) (sizeOnDisk SizeOnDisk, err error)
+	IsTxExecutedInBlock(params IsTxExecutedInBlockParams) (executed bool, err error)
+	IsTxExecutedInBlockContext(ctx context.Context, params IsTxExecutedInBlockParams) (executed bool, err error)
+	GetAccountRegistrationTopoheight(addr string) (topoheight uint64, err error)
+	GetAccountRegistrationTopoheightContext(ctx context.Context, addr string) (topoheight uint64, err error)
+	IsAccountRegistered(params IsAccountRegisteredParams) (exists bool, err error)
+	IsAccountRegisteredContext(ctx context.Context, params IsAccountRegisteredParams) (exists bool, err error)
+	GetDifficulty() (result GetDifficultyResult, err error)
+	GetDifficultyContext(ctx context.Context) (result GetDifficultyResult, err error)
+	ValidateAddress(params ValidateAddressParams) (result ValidateAddressResult, err error)
+	ValidateAddressContext(ctx context.Context, params ValidateAddressParams) (result ValidateAddressResult, err error)
+	ExtractKeyFromAddress(params ExtractKeyFromAddressParams) (key interface{}, err error)
+	ExtractKeyFromAddressContext(ctx context.Context, params ExtractKeyFromAddressParams) (key interface{}, err error)
+	GetMinerWork(params GetMinerWorkParams) (result GetMinerWorkResult, err error)
+	GetMinerWorkContext(ctx context.Context, params GetMinerWorkParams) (result GetMinerWorkResult, err error)
+	SplitAddress(params SplitAddressParams) (result SplitAddressResult, err error)
+	SplitAddressContext(ctx context.Context, params SplitAddressParams) (result SplitAddressResult, err error)
+}
+
+var _ Client = (*RPC)(nil)
+var _ Client = (*WebSocket)(nil)
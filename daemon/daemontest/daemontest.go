@@ -0,0 +1,455 @@
+// Package daemontest provides an in-process XELIS daemon for tests.
+// It serves the JSON-RPC methods of the daemon package over HTTP and WebSocket from an in-memory chain.
+package daemontest
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"sort"
+	"sync"
+
+	"github.com/xelis-project/xelis-go-sdk/block"
+	"github.com/xelis-project/xelis-go-sdk/config"
+	"github.com/xelis-project/xelis-go-sdk/daemon"
+	"github.com/xelis-project/xelis-go-sdk/rpc/rpctest"
+)
+
+// Number of blocks behind the top before a block is considered stable.
+const STABLE_LIMIT = config.STABLE_LIMIT
+
+// Maximum number of blocks returned by the range methods.
+const MAX_BLOCKS = config.MAX_BLOCKS_RANGE
+
+const TESTING_MINER = "xet:62wnkswt0rmrdd9d2lawgpzuh87fkpmp4gx9j3g4u24yrdkdxgksqnuuucf"
+
+type versionedBalance struct {
+	topoheight uint64
+	balance    daemon.VersionedBalance
+}
+
+type versionedNonce struct {
+	topoheight uint64
+	nonce      uint64
+}
+
+type Server struct {
+	*rpctest.Server
+
+	Network         string
+	Version         string
+	BlockTimeTarget uint64 // in milliseconds
+	Difficulty      string
+	BlockReward     uint64
+	DevFees         []daemon.Fee
+
+	mutex         sync.Mutex
+	counter       uint64
+	blocks        []daemon.Block // indexed by topoheight
+	txs           map[string]daemon.Transaction
+	mempool       []string
+	balances      map[string]map[string][]versionedBalance
+	nonces        map[string][]versionedNonce
+	registrations map[string]uint64
+	history       map[string][]daemon.AccountHistory
+	assets        map[string]daemon.Asset
+	peers         []daemon.Peer
+}
+
+// Start a daemon with a genesis block at topoheight 0.
+// Call Close when done.
+func NewServer() *Server {
+	s := &Server{
+		Server:          rpctest.NewServer(),
+		Network:         "Testnet",
+		Version:         "1.13.0",
+		BlockTimeTarget: 15000,
+		Difficulty:      "150000",
+		BlockReward:     146229945,
+		DevFees: []daemon.Fee{
+			{FeePercentage: 10, Height: 0},
+			{FeePercentage: 5, Height: 3250000},
+		},
+		txs:           make(map[string]daemon.Transaction),
+		balances:      make(map[string]map[string][]versionedBalance),
+		nonces:        make(map[string][]versionedNonce),
+		registrations: make(map[string]uint64),
+		history:       make(map[string][]daemon.AccountHistory),
+		assets:        make(map[string]daemon.Asset),
+	}
+
+	s.assets[config.XELIS_ASSET] = daemon.Asset{Topoheight: 0, Decimals: 8}
+	s.registerHandlers()
+	s.AddBlock(daemon.Block{})
+	return s
+}
+
+// Deterministic 32 bytes hex hash, unique for the server.
+func (s *Server) newHash() string {
+	s.counter++
+	data := make([]byte, 8)
+	binary.BigEndian.PutUint64(data, s.counter)
+	hash := sha256.Sum256(data)
+	return hex.EncodeToString(hash[:])
+}
+
+// Hash of the block header, or a unique hash if the block fields can't be encoded.
+func (s *Server) blockHash(b daemon.Block) string {
+	header, err := block.NewHeaderFromBlock(b)
+	if err != nil {
+		return s.newHash()
+	}
+
+	return header.Hash().String()
+}
+
+func (s *Server) top() *daemon.Block {
+	return &s.blocks[len(s.blocks)-1]
+}
+
+func (s *Server) topoheight() uint64 {
+	return uint64(len(s.blocks) - 1)
+}
+
+func (s *Server) stableTopoheight() uint64 {
+	topoheight := s.topoheight()
+	if topoheight < STABLE_LIMIT {
+		return 0
+	}
+
+	return topoheight - STABLE_LIMIT
+}
+
+func (s *Server) stableHeight() uint64 {
+	height := s.top().Height
+	if height < STABLE_LIMIT {
+		return 0
+	}
+
+	return height - STABLE_LIMIT
+}
+
+// Order the block at the next topoheight.
+// Missing fields (hash, height, tips, timestamp, ...) are filled from the current top block.
+// Transactions listed in TxsHashes are executed in the block and removed from the mempool.
+// Emits new_block, block_ordered and transaction_executed events.
+func (s *Server) AddBlock(block daemon.Block) daemon.Block {
+	s.mutex.Lock()
+	block, executed := s.addBlock(block)
+	s.mutex.Unlock()
+
+	s.notifyBlock(block, executed)
+	return block
+}
+
+func (s *Server) addBlock(block daemon.Block) (daemon.Block, []daemon.TransactionExecutedResult) {
+	topoheight := uint64(len(s.blocks))
+	block.Topoheight = &topoheight
+
+	if block.BlockType == "" {
+		block.BlockType = "Normal"
+	}
+
+	if block.Difficulty == "" {
+		block.Difficulty = s.Difficulty
+	}
+
+	if block.Miner == "" {
+		block.Miner = TESTING_MINER
+	}
+
+	// unique so that blocks replaced by ReorgFrom get another hash
+	if block.ExtraNonce == "" {
+		block.ExtraNonce = s.newHash()
+	}
+
+	if block.Reward == nil {
+		reward := s.BlockReward
+		devReward := reward * s.devFeePercentage(block.Height) / 100
+		minerReward := reward - devReward
+		block.Reward = &reward
+		block.MinerReward = &minerReward
+		block.DevReward = &devReward
+	}
+
+	if block.TxsHashes == nil {
+		block.TxsHashes = []string{}
+	}
+
+	if len(s.blocks) > 0 {
+		top := s.top()
+		if block.Height == 0 {
+			block.Height = top.Height + 1
+		}
+
+		if block.Tips == nil {
+			block.Tips = []string{top.Hash}
+		}
+
+		if block.Timestamp == 0 {
+			block.Timestamp = top.Timestamp + s.BlockTimeTarget
+		}
+
+		if block.Supply == nil {
+			supply := *top.Supply + *block.Reward
+			block.Supply = &supply
+		}
+
+		if block.CumulativeDifficulty == "" {
+			block.CumulativeDifficulty = top.CumulativeDifficulty
+		}
+	} else {
+		if block.Tips == nil {
+			block.Tips = []string{}
+		}
+
+		if block.Timestamp == 0 {
+			block.Timestamp = 1696132639000
+		}
+
+		if block.Supply == nil {
+			supply := *block.Reward
+			block.Supply = &supply
+		}
+
+		if block.CumulativeDifficulty == "" {
+			block.CumulativeDifficulty = block.Difficulty
+		}
+	}
+
+	if block.Hash == "" {
+		block.Hash = s.blockHash(block)
+	}
+
+	var executed []daemon.TransactionExecutedResult
+	var totalFees uint64
+	for _, txHash := range block.TxsHashes {
+		tx, ok := s.txs[txHash]
+		if !ok {
+			continue
+		}
+
+		tx.Blocks = append(tx.Blocks, block.Hash)
+		if tx.ExecutedInBlock == nil {
+			blockHash := block.Hash
+			tx.ExecutedInBlock = &blockHash
+			tx.InMempool = false
+			s.removeFromMempool(txHash)
+			totalFees += tx.Fee
+
+			executed = append(executed, daemon.TransactionExecutedResult{
+				BlockHash:  block.Hash,
+				Topoheight: topoheight,
+				TxHash:     txHash,
+			})
+		}
+
+		s.txs[txHash] = tx
+	}
+
+	if block.TotalFees == nil {
+		block.TotalFees = &totalFees
+	}
+
+	s.blocks = append(s.blocks, block)
+	return block, executed
+}
+
+func (s *Server) notifyBlock(block daemon.Block, executed []daemon.TransactionExecutedResult) {
+	s.EmitNewBlock(block)
+	s.EmitBlockOrdered(block)
+	for _, result := range executed {
+		s.EmitTransactionExecuted(result)
+	}
+}
+
+func (s *Server) devFeePercentage(height uint64) uint64 {
+	percentage := uint64(0)
+	for _, fee := range s.DevFees {
+		if height >= fee.Height {
+			percentage = uint64(fee.FeePercentage)
+		}
+	}
+
+	return percentage
+}
+
+// Add a block containing the given transactions, or every transaction of the mempool if none is given.
+func (s *Server) MineBlock(txHashes ...string) daemon.Block {
+	s.mutex.Lock()
+	if len(txHashes) == 0 {
+		txHashes = append(txHashes, s.mempool...)
+	}
+	s.mutex.Unlock()
+
+	return s.AddBlock(daemon.Block{TxsHashes: txHashes})
+}
+
+// Replace every block from topoheight with the given blocks, like a DAG reorganisation.
+// Transactions executed in the removed blocks go back to the mempool unless a new block executes them again.
+func (s *Server) ReorgFrom(topoheight uint64, blocks ...daemon.Block) []daemon.Block {
+	s.mutex.Lock()
+	if topoheight == 0 || topoheight > uint64(len(s.blocks)) {
+		s.mutex.Unlock()
+		return nil
+	}
+
+	removed := append([]daemon.Block{}, s.blocks[topoheight:]...)
+	s.blocks = s.blocks[:topoheight]
+	for _, block := range removed {
+		for _, txHash := range block.TxsHashes {
+			tx, ok := s.txs[txHash]
+			if !ok {
+				continue
+			}
+
+			tx.Blocks = nil
+			if tx.ExecutedInBlock != nil && *tx.ExecutedInBlock == block.Hash {
+				tx.ExecutedInBlock = nil
+				tx.InMempool = true
+				s.mempool = append(s.mempool, txHash)
+			}
+
+			s.txs[txHash] = tx
+		}
+	}
+
+	var added []daemon.Block
+	var executed [][]daemon.TransactionExecutedResult
+	for _, block := range blocks {
+		block, results := s.addBlock(block)
+		added = append(added, block)
+		executed = append(executed, results)
+	}
+	s.mutex.Unlock()
+
+	for i, block := range added {
+		s.notifyBlock(block, executed[i])
+	}
+
+	return added
+}
+
+func (s *Server) removeFromMempool(txHash string) {
+	for i, hash := range s.mempool {
+		if hash == txHash {
+			s.mempool = append(s.mempool[:i], s.mempool[i+1:]...)
+			return
+		}
+	}
+}
+
+// Add the transaction to the mempool and emit transaction_added_in_mempool.
+// A hash is generated if the transaction doesn't have one.
+func (s *Server) AddTransaction(tx daemon.Transaction) daemon.Transaction {
+	s.mutex.Lock()
+	if tx.Hash == "" {
+		tx.Hash = s.newHash()
+	}
+
+	if tx.Blocks == nil {
+		tx.Blocks = []string{}
+	}
+
+	tx.InMempool = true
+	tx.ExecutedInBlock = nil
+	s.txs[tx.Hash] = tx
+	s.mempool = append(s.mempool, tx.Hash)
+	s.mutex.Unlock()
+
+	s.Notify(daemon.TransactionAddedInMempool, tx)
+	return tx
+}
+
+// Set the balance version of an account at topoheight. The account is registered if needed.
+func (s *Server) SetBalance(addr string, asset string, topoheight uint64, balance daemon.VersionedBalance) {
+	defer s.mutex.Unlock()
+	s.mutex.Lock()
+
+	assets, ok := s.balances[addr]
+	if !ok {
+		assets = make(map[string][]versionedBalance)
+		s.balances[addr] = assets
+	}
+
+	versions := append(assets[asset], versionedBalance{topoheight: topoheight, balance: balance})
+	sort.Slice(versions, func(i, j int) bool { return versions[i].topoheight < versions[j].topoheight })
+	assets[asset] = versions
+	s.registerAccount(addr, topoheight)
+}
+
+// Set the nonce of an account at topoheight. The account is registered if needed.
+func (s *Server) SetNonce(addr string, topoheight uint64, nonce uint64) {
+	defer s.mutex.Unlock()
+	s.mutex.Lock()
+
+	versions := append(s.nonces[addr], versionedNonce{topoheight: topoheight, nonce: nonce})
+	sort.Slice(versions, func(i, j int) bool { return versions[i].topoheight < versions[j].topoheight })
+	s.nonces[addr] = versions
+	s.registerAccount(addr, topoheight)
+}
+
+func (s *Server) RegisterAccount(addr string, topoheight uint64) {
+	defer s.mutex.Unlock()
+	s.mutex.Lock()
+	s.registerAccount(addr, topoheight)
+}
+
+func (s *Server) registerAccount(addr string, topoheight uint64) {
+	registered, ok := s.registrations[addr]
+	if !ok || topoheight < registered {
+		s.registrations[addr] = topoheight
+	}
+}
+
+func (s *Server) AddAccountHistory(addr string, history ...daemon.AccountHistory) {
+	defer s.mutex.Unlock()
+	s.mutex.Lock()
+	s.history[addr] = append(s.history[addr], history...)
+}
+
+func (s *Server) AddAsset(asset string, data daemon.Asset) {
+	defer s.mutex.Unlock()
+	s.mutex.Lock()
+	s.assets[asset] = data
+}
+
+// Add a peer and emit peer_connected.
+func (s *Server) AddPeer(peer daemon.Peer) {
+	s.mutex.Lock()
+	s.peers = append(s.peers, peer)
+	s.mutex.Unlock()
+
+	s.Notify(daemon.PeerConnected, peer)
+}
+
+// Remove a peer and emit peer_disconnect.
+func (s *Server) RemovePeer(id uint64) {
+	s.mutex.Lock()
+	for i, peer := range s.peers {
+		if peer.Id == id {
+			s.peers = append(s.peers[:i], s.peers[i+1:]...)
+			break
+		}
+	}
+	s.mutex.Unlock()
+
+	s.Notify(daemon.PeerDisconnected, id)
+}
+
+func (s *Server) EmitNewBlock(block daemon.Block) {
+	s.Notify(daemon.NewBlock, block)
+}
+
+// Same payload as the daemon block_ordered event.
+func (s *Server) EmitBlockOrdered(block daemon.Block) {
+	s.Notify(daemon.BlockOrdered, daemon.BlockOrderedResult{
+		BlockHash:  block.Hash,
+		BlockType:  block.BlockType,
+		Topoheight: *block.Topoheight,
+	})
+}
+
+func (s *Server) EmitTransactionExecuted(result daemon.TransactionExecutedResult) {
+	s.Notify(daemon.TransactionExecuted, result)
+}
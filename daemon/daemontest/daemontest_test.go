@@ -0,0 +1,490 @@
+package daemontest
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/xelis-project/xelis-go-sdk/block"
+	"github.com/xelis-project/xelis-go-sdk/config"
+	"github.com/xelis-project/xelis-go-sdk/daemon"
+	"github.com/xelis-project/xelis-go-sdk/rpc"
+)
+
+const TESTING_ADDR = "xet:62wnkswt0rmrdd9d2lawgpzuh87fkpmp4gx9j3g4u24yrdkdxgksqnuuucf"
+const INTEGRATED_ADDR = "xet:6eadzwf5xdacts6fs4y3csmnsmy4mcxewqt3xyygwfx0hm0tm32szqsrqyzkjar9d4esyqgpq4ehwmmjvsqqypgpq45x2mrvduqqzpthdaexceqpq4mk7unywvqsgqqpq4yx2mrvduqqzp2hdaexceqqqyzxvun0d5qqzp2cg4xyj5ct5udlg"
+
+func useServer(t *testing.T) (server *Server, ws *daemon.WebSocket, clients map[string]daemon.Client) {
+	server = NewServer()
+	t.Cleanup(server.Close)
+
+	rpcClient, err := daemon.NewRPC(context.Background(), server.RPCEndpoint())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ws, err = daemon.NewWebSocket(server.WSEndpoint())
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { ws.Close() })
+
+	clients = map[string]daemon.Client{"rpc": rpcClient, "ws": ws}
+	return
+}
+
+func TestGetInfo(t *testing.T) {
+	server, _, clients := useServer(t)
+	server.MineBlock()
+	server.MineBlock()
+
+	for name, client := range clients {
+		info, err := client.GetInfo()
+		if err != nil {
+			t.Fatal(name, err)
+		}
+
+		if info.Topoheight != 2 || info.Height != 2 {
+			t.Errorf("%s: expected topoheight 2, got %+v", name, info)
+		}
+
+		version, err := client.GetVersion()
+		if err != nil {
+			t.Fatal(name, err)
+		}
+
+		if version != server.Version {
+			t.Errorf("%s: expected version %s, got %s", name, server.Version, version)
+		}
+	}
+}
+
+func TestNonceAndBalance(t *testing.T) {
+	server, _, clients := useServer(t)
+	for i := 0; i < 10; i++ {
+		server.MineBlock()
+	}
+
+	server.SetNonce(TESTING_ADDR, 1, 5)
+	server.SetBalance(TESTING_ADDR, config.XELIS_ASSET, 1, daemon.VersionedBalance{BalanceType: daemon.BalanceInput})
+	server.SetBalance(TESTING_ADDR, config.XELIS_ASSET, 9, daemon.VersionedBalance{BalanceType: daemon.BalanceBoth})
+
+	for name, client := range clients {
+		hasNonce, err := client.HasNonce(TESTING_ADDR)
+		if err != nil {
+			t.Fatal(name, err)
+		}
+
+		hasBalance, err := client.HasBalance(daemon.GetBalanceParams{Address: TESTING_ADDR, Asset: config.XELIS_ASSET})
+		if err != nil {
+			t.Fatal(name, err)
+		}
+
+		if !hasNonce || !hasBalance {
+			t.Errorf("%s: expected nonce and balance", name)
+		}
+
+		nonce, err := client.GetNonce(TESTING_ADDR)
+		if err != nil {
+			t.Fatal(name, err)
+		}
+
+		if nonce.Nonce != 5 || nonce.Topoheight != 1 {
+			t.Errorf("%s: unexpected nonce %+v", name, nonce)
+		}
+
+		balance, err := client.GetBalance(daemon.GetBalanceParams{Address: TESTING_ADDR, Asset: config.XELIS_ASSET})
+		if err != nil {
+			t.Fatal(name, err)
+		}
+
+		if balance.Topoheight != 9 || balance.Version.BalanceType != daemon.BalanceBoth {
+			t.Errorf("%s: unexpected balance %+v", name, balance)
+		}
+
+		stableBalance, err := client.GetStableBalance(daemon.GetBalanceParams{Address: TESTING_ADDR, Asset: config.XELIS_ASSET})
+		if err != nil {
+			t.Fatal(name, err)
+		}
+
+		if stableBalance.StableTopoheight != 2 || stableBalance.Version.BalanceType != daemon.BalanceInput {
+			t.Errorf("%s: unexpected stable balance %+v", name, stableBalance)
+		}
+
+		registered, err := client.IsAccountRegistered(daemon.IsAccountRegisteredParams{Address: TESTING_ADDR, InStableHeight: true})
+		if err != nil {
+			t.Fatal(name, err)
+		}
+
+		if !registered {
+			t.Errorf("%s: expected account to be registered", name)
+		}
+	}
+}
+
+func TestSubmitTransaction(t *testing.T) {
+	server, _, clients := useServer(t)
+
+	for name, client := range clients {
+		_, err := client.SubmitTransaction("00" + name)
+		if err == nil {
+			t.Errorf("%s: expected invalid hex error", name)
+		}
+
+		ok, err := client.SubmitTransaction("0001020304" + map[string]string{"rpc": "aa", "ws": "bb"}[name])
+		if err != nil {
+			t.Fatal(name, err)
+		}
+
+		if !ok {
+			t.Errorf("%s: transaction not accepted", name)
+		}
+
+		mempool, err := client.GetMempool()
+		if err != nil {
+			t.Fatal(name, err)
+		}
+
+		if len(mempool) != 1 || !mempool[0].InMempool {
+			t.Fatalf("%s: expected one transaction in mempool, got %+v", name, mempool)
+		}
+
+		block := server.MineBlock()
+		tx, err := client.GetTransaction(mempool[0].Hash)
+		if err != nil {
+			t.Fatal(name, err)
+		}
+
+		if tx.ExecutedInBlock == nil || *tx.ExecutedInBlock != block.Hash {
+			t.Errorf("%s: expected transaction executed in %s, got %+v", name, block.Hash, tx)
+		}
+
+		executed, err := client.IsTxExecutedInBlock(daemon.IsTxExecutedInBlockParams{TxHash: tx.Hash, BlockHash: block.Hash})
+		if err != nil {
+			t.Fatal(name, err)
+		}
+
+		if !executed {
+			t.Errorf("%s: expected transaction executed in block", name)
+		}
+	}
+}
+
+func TestBlocksRange(t *testing.T) {
+	server, _, clients := useServer(t)
+	for i := 0; i < 30; i++ {
+		server.MineBlock()
+	}
+
+	for name, client := range clients {
+		blocks, err := client.GetBlocksRangeByTopoheight(daemon.GetTopoheightRangeParams{StartTopoheight: 10, EndTopoheight: 19})
+		if err != nil {
+			t.Fatal(name, err)
+		}
+
+		hashes, err := client.GetDAGOrder(daemon.GetTopoheightRangeParams{StartTopoheight: 10, EndTopoheight: 19})
+		if err != nil {
+			t.Fatal(name, err)
+		}
+
+		if len(blocks) != 10 || len(hashes) != 10 {
+			t.Fatalf("%s: expected 10 blocks, got %d and %d", name, len(blocks), len(hashes))
+		}
+
+		for i, block := range blocks {
+			if *block.Topoheight != uint64(10+i) || block.Hash != hashes[i] {
+				t.Errorf("%s: unexpected block %+v", name, block)
+			}
+		}
+
+		_, err = client.GetBlocksRangeByTopoheight(daemon.GetTopoheightRangeParams{StartTopoheight: 0, EndTopoheight: 25})
+		if err == nil {
+			t.Errorf("%s: expected range error", name)
+		}
+
+		byHash, err := client.GetBlockByHash(daemon.GetBlockByHashParams{Hash: blocks[0].Hash})
+		if err != nil {
+			t.Fatal(name, err)
+		}
+
+		if byHash.Height != blocks[0].Height {
+			t.Errorf("%s: unexpected block %+v", name, byHash)
+		}
+
+		atHeight, err := client.GetBlocksAtHeight(daemon.GetBlocksAtHeightParams{Height: 3})
+		if err != nil {
+			t.Fatal(name, err)
+		}
+
+		if len(atHeight) != 1 {
+			t.Errorf("%s: expected one block at height 3, got %d", name, len(atHeight))
+		}
+	}
+}
+
+func TestReorgFrom(t *testing.T) {
+	server, _, clients := useServer(t)
+	for i := 0; i < 5; i++ {
+		server.MineBlock()
+	}
+
+	tx := server.AddTransaction(daemon.Transaction{Fee: 100})
+	orphan := server.MineBlock(tx.Hash)
+	server.ReorgFrom(*orphan.Topoheight, daemon.Block{}, daemon.Block{})
+
+	for name, client := range clients {
+		block, err := client.GetBlockAtTopoheight(daemon.GetBlockAtTopoheightParams{Topoheight: *orphan.Topoheight})
+		if err != nil {
+			t.Fatal(name, err)
+		}
+
+		if block.Hash == orphan.Hash {
+			t.Errorf("%s: expected a new block at topoheight %d", name, *orphan.Topoheight)
+		}
+
+		result, err := client.GetTransaction(tx.Hash)
+		if err != nil {
+			t.Fatal(name, err)
+		}
+
+		if !result.InMempool || result.ExecutedInBlock != nil {
+			t.Errorf("%s: expected transaction back in mempool, got %+v", name, result)
+		}
+	}
+}
+
+func TestMining(t *testing.T) {
+	server, _, clients := useServer(t)
+	tx := server.AddTransaction(daemon.Transaction{Fee: 100})
+
+	for name, client := range clients {
+		template, err := client.GetBlockTemplate(TESTING_ADDR)
+		if err != nil {
+			t.Fatal(name, err)
+		}
+
+		header, err := block.DecodeHeaderHex(template.Template)
+		if err != nil {
+			t.Fatal(name, err)
+		}
+
+		result, err := client.GetMinerWork(daemon.GetMinerWorkParams{Template: template.Template})
+		if err != nil {
+			t.Fatal(name, err)
+		}
+
+		work, err := block.DecodeMinerWorkHex(result.MinerWork)
+		if err != nil {
+			t.Fatal(name, err)
+		}
+
+		if work.WorkHash != header.WorkHash() || header.Height != template.Height {
+			t.Errorf("%s: unexpected miner work %+v", name, work)
+		}
+
+		work.Nonce = 1234
+		minerWork := work.EncodeHex()
+		_, err = client.SubmitBlock(daemon.SubmitBlockParams{BlockTemplate: template.Template, MinerWork: &minerWork})
+		if err != nil {
+			t.Fatal(name, err)
+		}
+
+		mined, err := client.GetBlockByHash(daemon.GetBlockByHashParams{Hash: work.Hash().String()})
+		if err != nil {
+			t.Fatal(name, err)
+		}
+
+		err = block.VerifyBlockHash(mined)
+		if err != nil {
+			t.Error(name, err)
+		}
+
+		if mined.Nonce != 1234 || mined.Miner != TESTING_ADDR {
+			t.Errorf("%s: unexpected block %+v", name, mined)
+		}
+	}
+
+	result, err := clients["rpc"].GetTransaction(tx.Hash)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if result.InMempool {
+		t.Error("Expected transaction to be executed by the mined block")
+	}
+
+	_, err = clients["rpc"].SubmitBlock(daemon.SubmitBlockParams{BlockTemplate: "00"})
+	if !errors.Is(err, rpc.ErrInvalidParams) {
+		t.Errorf("Expected invalid params error, got %v", err)
+	}
+}
+
+func TestAddress(t *testing.T) {
+	_, _, clients := useServer(t)
+
+	for name, client := range clients {
+		valid, err := client.ValidateAddress(daemon.ValidateAddressParams{Address: TESTING_ADDR})
+		if err != nil {
+			t.Fatal(name, err)
+		}
+
+		if !valid.IsValid || valid.IsIntegrated {
+			t.Errorf("%s: unexpected result %+v", name, valid)
+		}
+
+		key, err := client.ExtractKeyFromAddress(daemon.ExtractKeyFromAddressParams{Address: TESTING_ADDR, AsHex: true})
+		if err != nil {
+			t.Fatal(name, err)
+		}
+
+		if len(key.(string)) != 64 {
+			t.Errorf("%s: unexpected key %v", name, key)
+		}
+
+		split, err := client.SplitAddress(daemon.SplitAddressParams{Address: INTEGRATED_ADDR})
+		if err != nil {
+			t.Fatal(name, err)
+		}
+
+		if split.IntegratedData.Fields["hello"].Value != "world" || len(split.IntegratedData.Fields["words"].Array) != 4 {
+			t.Errorf("%s: unexpected integrated data %+v", name, split.IntegratedData)
+		}
+	}
+}
+
+func TestUnknownMethod(t *testing.T) {
+	server, ws, _ := useServer(t)
+
+	rpcClient, err := daemon.NewRPC(context.Background(), server.RPCEndpoint())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = rpcClient.Client.Call(context.Background(), "unknown_method", nil)
+	if err == nil {
+		t.Error("Expected an error")
+	}
+
+	res, err := ws.WS.Call("unknown_method", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if res.Error == nil {
+		t.Error("Expected an error")
+	}
+}
+
+func TestEvents(t *testing.T) {
+	server, ws, _ := useServer(t)
+
+	newBlock, newBlockErr, err := ws.NewBlockChannel()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	executed, executedErr, err := ws.TransactionExecutedChannel()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tx := server.AddTransaction(daemon.Transaction{})
+	go server.MineBlock(tx.Hash)
+
+	select {
+	case block := <-newBlock:
+		if len(block.TxsHashes) != 1 || block.TxsHashes[0] != tx.Hash {
+			t.Errorf("unexpected block %+v", block)
+		}
+	case err := <-newBlockErr:
+		t.Fatal(err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("new_block was not received")
+	}
+
+	select {
+	case result := <-executed:
+		if result.TxHash != tx.Hash {
+			t.Errorf("unexpected result %+v", result)
+		}
+	case err := <-executedErr:
+		t.Fatal(err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("transaction_executed was not received")
+	}
+}
+
+func TestErrors(t *testing.T) {
+	_, _, clients := useServer(t)
+
+	for name, client := range clients {
+		_, err := client.GetBlockAtTopoheight(daemon.GetBlockAtTopoheightParams{Topoheight: 100})
+		if !errors.Is(err, daemon.ErrBlockNotFound) || errors.Is(err, daemon.ErrAccountNotFound) {
+			t.Errorf("%s: expected block not found, got %v", name, err)
+		}
+
+		_, err = client.GetNonce(TESTING_ADDR)
+		if !errors.Is(err, daemon.ErrNonceNotFound) {
+			t.Errorf("%s: expected nonce not found, got %v", name, err)
+		}
+
+		_, err = client.GetBlocksRangeByTopoheight(daemon.GetTopoheightRangeParams{StartTopoheight: 5, EndTopoheight: 1})
+		var rpcErr *rpc.RPCError
+		if !errors.As(err, &rpcErr) || rpcErr.Code != rpc.InvalidParamsCode {
+			t.Errorf("%s: expected invalid params, got %v", name, err)
+		}
+	}
+}
+
+func TestBatch(t *testing.T) {
+	server, _, _ := useServer(t)
+	for i := 0; i < 5; i++ {
+		server.MineBlock()
+	}
+
+	rpcClient, err := daemon.NewRPC(context.Background(), server.RPCEndpoint())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	batch := rpcClient.Batch()
+	var blocks []*daemon.BatchCall[daemon.Block]
+	for topoheight := uint64(0); topoheight <= 5; topoheight++ {
+		blocks = append(blocks, batch.GetBlockAtTopoheight(daemon.GetBlockAtTopoheightParams{Topoheight: topoheight}))
+	}
+
+	missing := batch.GetBlockAtTopoheight(daemon.GetBlockAtTopoheightParams{Topoheight: 100})
+	version := daemon.Queue[string](batch, daemon.GetVersion, nil)
+
+	if batch.Len() != 8 {
+		t.Fatalf("Expected 8 queued calls, got %d", batch.Len())
+	}
+
+	err = batch.Send()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i, block := range blocks {
+		if block.Err != nil {
+			t.Fatal(block.Err)
+		}
+
+		if *block.Result.Topoheight != uint64(i) {
+			t.Errorf("Expected topoheight %d, got %d", i, *block.Result.Topoheight)
+		}
+	}
+
+	if !errors.Is(missing.Err, daemon.ErrBlockNotFound) {
+		t.Errorf("Expected block not found, got %v", missing.Err)
+	}
+
+	if version.Err != nil || version.Result != server.Version {
+		t.Errorf("Unexpected version %s: %v", version.Result, version.Err)
+	}
+
+	if batch.Len() != 0 {
+		t.Errorf("Expected batch to be reset")
+	}
+}
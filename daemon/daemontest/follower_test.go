@@ -0,0 +1,196 @@
+package daemontest
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/xelis-project/xelis-go-sdk/daemon"
+)
+
+// Records the applied chain as seen by the callbacks.
+type followerRecorder struct {
+	mutex    sync.Mutex
+	chain    []string
+	reverted []uint64
+}
+
+func (r *followerRecorder) options() daemon.FollowerOptions {
+	return daemon.FollowerOptions{
+		PollInterval: 20 * time.Millisecond,
+		OnApply: func(block daemon.Block) error {
+			defer r.mutex.Unlock()
+			r.mutex.Lock()
+
+			if *block.Topoheight != uint64(len(r.chain)) {
+				return fmt.Errorf("expected topoheight %d, got %d", len(r.chain), *block.Topoheight)
+			}
+
+			r.chain = append(r.chain, block.Hash)
+			return nil
+		},
+		OnRevert: func(block daemon.Block) error {
+			defer r.mutex.Unlock()
+			r.mutex.Lock()
+
+			if *block.Topoheight != uint64(len(r.chain)-1) || r.chain[*block.Topoheight] != block.Hash {
+				return fmt.Errorf("unexpected revert of %s at topoheight %d", block.Hash, *block.Topoheight)
+			}
+
+			r.chain = r.chain[:len(r.chain)-1]
+			r.reverted = append(r.reverted, *block.Topoheight)
+			return nil
+		},
+	}
+}
+
+func (r *followerRecorder) waitChain(t *testing.T, server *Server, errs chan error) {
+	hashes := server.dagOrder()
+	deadline := time.After(5 * time.Second)
+	for {
+		r.mutex.Lock()
+		done := fmt.Sprint(r.chain) == fmt.Sprint(hashes)
+		r.mutex.Unlock()
+
+		if done {
+			return
+		}
+
+		select {
+		case err := <-errs:
+			t.Fatal(err)
+		case <-deadline:
+			t.Fatalf("follower did not reach topoheight %d", len(hashes)-1)
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+}
+
+func (s *Server) dagOrder() (hashes []string) {
+	defer s.mutex.Unlock()
+	s.mutex.Lock()
+
+	for _, block := range s.blocks {
+		hashes = append(hashes, block.Hash)
+	}
+
+	return
+}
+
+func runFollower(t *testing.T, follower *daemon.Follower) chan error {
+	ctx, cancel := context.WithCancel(context.Background())
+	errs := make(chan error, 1)
+	done := make(chan bool)
+	go func() {
+		err := follower.Run(ctx)
+		if err != context.Canceled {
+			errs <- err
+		}
+		close(done)
+	}()
+
+	t.Cleanup(func() {
+		cancel()
+		<-done
+	})
+
+	return errs
+}
+
+func TestFollowerReorg(t *testing.T) {
+	server, ws, clients := useServer(t)
+	for i := 0; i < 30; i++ {
+		server.MineBlock()
+	}
+
+	recorder := &followerRecorder{}
+	follower := daemon.NewFollower(clients["rpc"], ws, recorder.options())
+	errs := runFollower(t, follower)
+	recorder.waitChain(t, server, errs)
+
+	server.ReorgFrom(25, make([]daemon.Block, 7)...)
+	recorder.waitChain(t, server, errs)
+
+	expected := []uint64{30, 29, 28, 27, 26, 25}
+	if fmt.Sprint(recorder.reverted) != fmt.Sprint(expected) {
+		t.Errorf("Expected reverted topoheights %v, got %v", expected, recorder.reverted)
+	}
+
+	cursor, ok := follower.Cursor()
+	if !ok || cursor.Topoheight != 31 {
+		t.Errorf("Unexpected cursor %+v", cursor)
+	}
+}
+
+func TestFollowerResume(t *testing.T) {
+	server, _, clients := useServer(t)
+	for i := 0; i < 10; i++ {
+		server.MineBlock()
+	}
+
+	hashes := server.dagOrder()
+	recorder := &followerRecorder{chain: hashes[:6]}
+	options := recorder.options()
+	options.Cursor = &daemon.FollowerCursor{Topoheight: 5, Hash: hashes[5]}
+
+	// no WebSocket, only polling
+	follower := daemon.NewFollower(clients["ws"], nil, options)
+	errs := runFollower(t, follower)
+	recorder.waitChain(t, server, errs)
+
+	// shorter chain, the follower must revert the blocks above the new top
+	server.ReorgFrom(7, daemon.Block{})
+	recorder.waitChain(t, server, errs)
+
+	expected := []uint64{10, 9, 8, 7}
+	if fmt.Sprint(recorder.reverted) != fmt.Sprint(expected) {
+		t.Errorf("Expected reverted topoheights %v, got %v", expected, recorder.reverted)
+	}
+}
+
+// Stopping the follower must not remove the other listeners of the shared WebSocket.
+func TestFollowerSharedWebSocket(t *testing.T) {
+	server, ws, clients := useServer(t)
+
+	ordered := make(chan daemon.BlockOrderedResult, 100)
+	err := ws.BlockOrderedFunc(func(result daemon.BlockOrderedResult, err error) {
+		if err == nil {
+			ordered <- result
+		}
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	recorder := &followerRecorder{}
+	follower := daemon.NewFollower(clients["rpc"], ws, recorder.options())
+	ctx, cancel := context.WithCancel(context.Background())
+	errs := make(chan error, 1)
+	go func() {
+		errs <- follower.Run(ctx)
+	}()
+
+	server.MineBlock()
+	recorder.waitChain(t, server, make(chan error))
+
+	cancel()
+	if err := <-errs; err != context.Canceled {
+		t.Fatal(err)
+	}
+
+	server.MineBlock()
+	hashes := server.dagOrder()
+	top := hashes[len(hashes)-1]
+	for {
+		select {
+		case result := <-ordered:
+			if result.BlockHash == top {
+				return
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatal("block_ordered was not received after the follower stopped")
+		}
+	}
+}
@@ -0,0 +1,701 @@
+package daemontest
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"sort"
+
+	"github.com/xelis-project/xelis-go-sdk/address"
+	"github.com/xelis-project/xelis-go-sdk/block"
+	"github.com/xelis-project/xelis-go-sdk/daemon"
+	"github.com/xelis-project/xelis-go-sdk/rpc"
+	"github.com/xelis-project/xelis-go-sdk/rpc/rpctest"
+)
+
+type addressParams struct {
+	Address string `json:"address"`
+}
+
+type hashParams struct {
+	Hash string `json:"hash"`
+}
+
+type assetParams struct {
+	Asset string `json:"asset"`
+}
+
+type dataParams struct {
+	Data string `json:"data"`
+}
+
+type noParams struct{}
+
+// Register a method reading its params into P. The chain is locked while fn runs.
+func handle[P any](s *Server, method string, fn func(params P) (interface{}, *rpc.RPCError)) {
+	s.Register(method, func(raw json.RawMessage) (interface{}, *rpc.RPCError) {
+		var params P
+		err := rpctest.ParseParams(raw, &params)
+		if err != nil {
+			return nil, err
+		}
+
+		defer s.mutex.Unlock()
+		s.mutex.Lock()
+		return fn(params)
+	})
+}
+
+func errNotFound(format string, a ...interface{}) *rpc.RPCError {
+	return rpctest.NewError(rpc.InternalErrorCode, format, a...)
+}
+
+func (s *Server) registerHandlers() {
+	handle(s, daemon.GetVersion, func(noParams) (interface{}, *rpc.RPCError) {
+		return s.Version, nil
+	})
+
+	handle(s, daemon.GetInfo, func(noParams) (interface{}, *rpc.RPCError) {
+		top := s.top()
+		return daemon.GetInfoResult{
+			AverageBlocktime: s.BlockTimeTarget,
+			BlockReward:      *top.Reward,
+			MinerReward:      *top.MinerReward,
+			DevReward:        *top.DevReward,
+			BlockTimeTarget:  s.BlockTimeTarget,
+			Difficulty:       s.Difficulty,
+			Height:           top.Height,
+			MempoolSize:      uint64(len(s.mempool)),
+			NativeSupply:     *top.Supply,
+			Network:          s.Network,
+			PrunedTopoheight: 0,
+			Stableheight:     s.stableHeight(),
+			TopHash:          top.Hash,
+			Topoheight:       s.topoheight(),
+			Version:          s.Version,
+		}, nil
+	})
+
+	handle(s, daemon.GetHeight, func(noParams) (interface{}, *rpc.RPCError) {
+		return s.top().Height, nil
+	})
+
+	handle(s, daemon.GetTopoHeight, func(noParams) (interface{}, *rpc.RPCError) {
+		return s.topoheight(), nil
+	})
+
+	handle(s, daemon.GetStableHeight, func(noParams) (interface{}, *rpc.RPCError) {
+		return s.stableHeight(), nil
+	})
+
+	handle(s, daemon.GetStableTopoheight, func(noParams) (interface{}, *rpc.RPCError) {
+		return s.stableTopoheight(), nil
+	})
+
+	handle(s, daemon.GetStableBalance, func(params daemon.GetBalanceParams) (interface{}, *rpc.RPCError) {
+		stableTopoheight := s.stableTopoheight()
+		version, ok := s.balanceAt(params.Address, params.Asset, stableTopoheight, false)
+		if !ok {
+			return nil, errNotFound("Stable balance not found for %s", params.Address)
+		}
+
+		return daemon.GetStableBalanceResult{
+			StableTopoheight: stableTopoheight,
+			StableBlockHash:  s.blocks[stableTopoheight].Hash,
+			Version:          version.balance,
+		}, nil
+	})
+
+	handle(s, daemon.GetBlockTemplate, func(params addressParams) (interface{}, *rpc.RPCError) {
+		miner, err := address.NewAddressFromString(params.Address)
+		if err != nil || miner == nil {
+			return nil, rpctest.InvalidParams(fmt.Errorf("invalid address %s", params.Address))
+		}
+
+		header := s.template(miner)
+		template, err := header.EncodeHex()
+		if err != nil {
+			return nil, rpctest.NewError(rpc.InternalErrorCode, "%s", err)
+		}
+
+		return daemon.GetBlockTemplateResult{
+			Template:   template,
+			Algorithm:  "xel/v2",
+			Height:     header.Height,
+			Topoheight: s.topoheight() + 1,
+			Difficulty: s.Difficulty,
+		}, nil
+	})
+
+	handle(s, daemon.GetMinerWork, func(params daemon.GetMinerWorkParams) (interface{}, *rpc.RPCError) {
+		header, err := block.DecodeHeaderHex(params.Template)
+		if err != nil {
+			return nil, rpctest.InvalidParams(err)
+		}
+
+		work := header.MinerWork()
+		return daemon.GetMinerWorkResult{
+			MinerWork:  work.EncodeHex(),
+			Algorithm:  "xel/v2",
+			Height:     header.Height,
+			Difficulty: s.Difficulty,
+			Topoheight: s.topoheight() + 1,
+		}, nil
+	})
+
+	handle(s, daemon.GetBlockAtTopoheight, func(params daemon.GetBlockAtTopoheightParams) (interface{}, *rpc.RPCError) {
+		if params.Topoheight > s.topoheight() {
+			return nil, errNotFound("Block not found at topoheight %d", params.Topoheight)
+		}
+
+		return s.blocks[params.Topoheight], nil
+	})
+
+	handle(s, daemon.GetBlocksAtHeight, func(params daemon.GetBlocksAtHeightParams) (interface{}, *rpc.RPCError) {
+		blocks := []daemon.Block{}
+		for _, block := range s.blocks {
+			if block.Height == params.Height {
+				blocks = append(blocks, block)
+			}
+		}
+
+		return blocks, nil
+	})
+
+	handle(s, daemon.GetBlockByHash, func(params hashParams) (interface{}, *rpc.RPCError) {
+		block, ok := s.blockByHash(params.Hash)
+		if !ok {
+			return nil, errNotFound("Block not found with hash %s", params.Hash)
+		}
+
+		return block, nil
+	})
+
+	handle(s, daemon.GetTopBlock, func(params daemon.GetTopBlockParams) (interface{}, *rpc.RPCError) {
+		return *s.top(), nil
+	})
+
+	handle(s, daemon.GetNonce, func(params addressParams) (interface{}, *rpc.RPCError) {
+		versions := s.nonces[params.Address]
+		if len(versions) == 0 {
+			return nil, errNotFound("Nonce not found for %s", params.Address)
+		}
+
+		result := daemon.GetNonceResult{
+			Nonce:      versions[len(versions)-1].nonce,
+			Topoheight: versions[len(versions)-1].topoheight,
+		}
+
+		if len(versions) > 1 {
+			previous := versions[len(versions)-2].topoheight
+			result.PreviousTopoheight = &previous
+		}
+
+		return result, nil
+	})
+
+	handle(s, daemon.HasNonce, func(params addressParams) (interface{}, *rpc.RPCError) {
+		return map[string]bool{"exist": len(s.nonces[params.Address]) > 0}, nil
+	})
+
+	handle(s, daemon.GetNonceAtTopoheight, func(params daemon.GetNonceAtTopoheightParams) (interface{}, *rpc.RPCError) {
+		versions := s.nonces[params.Address]
+		for i, version := range versions {
+			if version.topoheight == params.Topoheight {
+				result := daemon.VersionedNonce{Nonce: version.nonce}
+				if i > 0 {
+					previous := versions[i-1].topoheight
+					result.PreviousTopoheight = &previous
+				}
+
+				return result, nil
+			}
+		}
+
+		return nil, errNotFound("Nonce not found for %s at topoheight %d", params.Address, params.Topoheight)
+	})
+
+	handle(s, daemon.GetBalance, func(params daemon.GetBalanceParams) (interface{}, *rpc.RPCError) {
+		version, ok := s.balanceAt(params.Address, params.Asset, s.topoheight(), false)
+		if !ok {
+			return nil, errNotFound("Balance not found for %s", params.Address)
+		}
+
+		return daemon.GetBalanceResult{Version: version.balance, Topoheight: version.topoheight}, nil
+	})
+
+	handle(s, daemon.HasBalance, func(params daemon.GetBalanceParams) (interface{}, *rpc.RPCError) {
+		_, ok := s.balanceAt(params.Address, params.Asset, s.topoheight(), false)
+		return map[string]bool{"exist": ok}, nil
+	})
+
+	handle(s, daemon.GetBalanceAtTopoheight, func(params daemon.GetBalanceAtTopoheightParams) (interface{}, *rpc.RPCError) {
+		version, ok := s.balanceAt(params.Address, params.Asset, params.Topoheight, true)
+		if !ok {
+			return nil, errNotFound("Balance not found for %s at topoheight %d", params.Address, params.Topoheight)
+		}
+
+		return version.balance, nil
+	})
+
+	handle(s, daemon.GetAsset, func(params assetParams) (interface{}, *rpc.RPCError) {
+		asset, ok := s.assets[params.Asset]
+		if !ok {
+			return nil, errNotFound("Asset not found: %s", params.Asset)
+		}
+
+		return asset, nil
+	})
+
+	handle(s, daemon.GetAssets, func(params daemon.GetAssetsParams) (interface{}, *rpc.RPCError) {
+		assets := []daemon.AssetWithData{}
+		for _, id := range sortedKeys(s.assets) {
+			asset := s.assets[id]
+			if !inTopoheightRange(asset.Topoheight, params) {
+				continue
+			}
+
+			assets = append(assets, daemon.AssetWithData{Asset: id, Topoheight: asset.Topoheight, Decimals: asset.Decimals})
+		}
+
+		return paginate(assets, params), nil
+	})
+
+	handle(s, daemon.CountAssets, func(noParams) (interface{}, *rpc.RPCError) {
+		return len(s.assets), nil
+	})
+
+	handle(s, daemon.CountTransactions, func(noParams) (interface{}, *rpc.RPCError) {
+		return len(s.txs), nil
+	})
+
+	handle(s, daemon.CountAccounts, func(noParams) (interface{}, *rpc.RPCError) {
+		return len(s.registrations), nil
+	})
+
+	handle(s, daemon.GetTips, func(noParams) (interface{}, *rpc.RPCError) {
+		return []string{s.top().Hash}, nil
+	})
+
+	handle(s, daemon.P2PStatus, func(noParams) (interface{}, *rpc.RPCError) {
+		topoheight := s.topoheight()
+		best := topoheight
+		for _, peer := range s.peers {
+			if peer.Topoheight > best {
+				best = peer.Topoheight
+			}
+		}
+
+		return daemon.P2PStatusResult{
+			BestTopoheight: best,
+			MaxPeers:       32,
+			OurTopoheight:  topoheight,
+			PeerCount:      uint64(len(s.peers)),
+			PeerId:         1,
+		}, nil
+	})
+
+	handle(s, daemon.GetDAGOrder, func(params daemon.GetTopoheightRangeParams) (interface{}, *rpc.RPCError) {
+		blocks, err := s.blocksRange(params.StartTopoheight, params.EndTopoheight)
+		if err != nil {
+			return nil, err
+		}
+
+		hashes := []string{}
+		for _, block := range blocks {
+			hashes = append(hashes, block.Hash)
+		}
+
+		return hashes, nil
+	})
+
+	s.Register(daemon.SubmitBlock, func(raw json.RawMessage) (interface{}, *rpc.RPCError) {
+		var params daemon.SubmitBlockParams
+		err := rpctest.ParseParams(raw, &params)
+		if err != nil {
+			return nil, err
+		}
+
+		header, decodeErr := block.DecodeHeaderHex(params.BlockTemplate)
+		if decodeErr != nil {
+			return nil, rpctest.InvalidParams(fmt.Errorf("invalid block template: %s", decodeErr))
+		}
+
+		if params.MinerWork != nil {
+			work, decodeErr := block.DecodeMinerWorkHex(*params.MinerWork)
+			if decodeErr != nil {
+				return nil, rpctest.InvalidParams(fmt.Errorf("invalid miner work: %s", decodeErr))
+			}
+
+			if work.WorkHash != header.WorkHash() {
+				return nil, rpctest.InvalidParams(fmt.Errorf("miner work doesn't match the block template"))
+			}
+
+			header.Timestamp = work.Timestamp
+			header.Nonce = work.Nonce
+			header.ExtraNonce = work.ExtraNonce
+			header.Miner = work.Miner
+		}
+
+		submitted, rpcErr := s.blockFromHeader(header)
+		if rpcErr != nil {
+			return nil, rpcErr
+		}
+
+		s.AddBlock(submitted)
+		return true, nil
+	})
+
+	s.Register(daemon.SubmitTransaction, func(raw json.RawMessage) (interface{}, *rpc.RPCError) {
+		var params dataParams
+		err := rpctest.ParseParams(raw, &params)
+		if err != nil {
+			return nil, err
+		}
+
+		data, decodeErr := hex.DecodeString(params.Data)
+		if len(data) == 0 || decodeErr != nil {
+			return nil, rpctest.InvalidParams(fmt.Errorf("invalid transaction data"))
+		}
+
+		// Not the real transaction hash, but stable for the same data.
+		sum := sha256.Sum256(data)
+		hash := hex.EncodeToString(sum[:])
+
+		s.mutex.Lock()
+		_, exists := s.txs[hash]
+		s.mutex.Unlock()
+
+		if exists {
+			return nil, rpctest.NewError(rpc.InternalErrorCode, "Transaction %s already in mempool", hash)
+		}
+
+		s.AddTransaction(daemon.Transaction{Hash: hash, Size: uint64(len(data))})
+		return true, nil
+	})
+
+	handle(s, daemon.GetMempool, func(noParams) (interface{}, *rpc.RPCError) {
+		txs := []daemon.Transaction{}
+		for _, hash := range s.mempool {
+			txs = append(txs, s.txs[hash])
+		}
+
+		return txs, nil
+	})
+
+	handle(s, daemon.GetTransaction, func(params hashParams) (interface{}, *rpc.RPCError) {
+		tx, ok := s.txs[params.Hash]
+		if !ok {
+			return nil, errNotFound("Transaction not found: %s", params.Hash)
+		}
+
+		return tx, nil
+	})
+
+	handle(s, daemon.GetTransactions, func(params daemon.GetTransactionsParams) (interface{}, *rpc.RPCError) {
+		txs := []*daemon.Transaction{}
+		for _, hash := range params.TxHashes {
+			tx, ok := s.txs[hash]
+			if ok {
+				txs = append(txs, &tx)
+			} else {
+				txs = append(txs, nil)
+			}
+		}
+
+		return txs, nil
+	})
+
+	handle(s, daemon.GetBlocksRangeByTopoheight, func(params daemon.GetTopoheightRangeParams) (interface{}, *rpc.RPCError) {
+		return s.blocksRange(params.StartTopoheight, params.EndTopoheight)
+	})
+
+	handle(s, daemon.GetBlocksRangeByHeight, func(params daemon.GetHeightRangeParams) (interface{}, *rpc.RPCError) {
+		if params.EndHeight < params.StartHeight || params.EndHeight-params.StartHeight >= MAX_BLOCKS {
+			return nil, rpctest.InvalidParams(fmt.Errorf("invalid range %d-%d", params.StartHeight, params.EndHeight))
+		}
+
+		blocks := []daemon.Block{}
+		for _, block := range s.blocks {
+			if block.Height >= params.StartHeight && block.Height <= params.EndHeight {
+				blocks = append(blocks, block)
+			}
+		}
+
+		return blocks, nil
+	})
+
+	handle(s, daemon.GetAccounts, func(params daemon.GetAccountsParams) (interface{}, *rpc.RPCError) {
+		accounts := []string{}
+		for _, addr := range sortedKeys(s.registrations) {
+			if inTopoheightRange(s.registrations[addr], params) {
+				accounts = append(accounts, addr)
+			}
+		}
+
+		return paginate(accounts, params), nil
+	})
+
+	handle(s, daemon.GetAccountHistory, func(params addressParams) (interface{}, *rpc.RPCError) {
+		_, ok := s.registrations[params.Address]
+		if !ok {
+			return nil, errNotFound("Account not found: %s", params.Address)
+		}
+
+		history := []daemon.AccountHistory{}
+		return append(history, s.history[params.Address]...), nil
+	})
+
+	handle(s, daemon.GetAccountAssets, func(params addressParams) (interface{}, *rpc.RPCError) {
+		return sortedKeys(s.balances[params.Address]), nil
+	})
+
+	handle(s, daemon.GetPeers, func(noParams) (interface{}, *rpc.RPCError) {
+		peers := append([]daemon.Peer{}, s.peers...)
+		return daemon.GetPeersResult{Peers: peers, TotalPeers: len(peers), HiddenPeers: 0}, nil
+	})
+
+	handle(s, daemon.GetDevFeeThresholds, func(noParams) (interface{}, *rpc.RPCError) {
+		return s.DevFees, nil
+	})
+
+	handle(s, daemon.GetSizeOnDisk, func(noParams) (interface{}, *rpc.RPCError) {
+		size := uint64(len(s.blocks)*1024 + len(s.txs)*2048)
+		return daemon.SizeOnDisk{SizeBytes: size, SizeFormatted: fmt.Sprintf("%d B", size)}, nil
+	})
+
+	handle(s, daemon.IsTxExecutedInBlock, func(params daemon.IsTxExecutedInBlockParams) (interface{}, *rpc.RPCError) {
+		tx, ok := s.txs[params.TxHash]
+		if !ok {
+			return nil, errNotFound("Transaction not found: %s", params.TxHash)
+		}
+
+		return tx.ExecutedInBlock != nil && *tx.ExecutedInBlock == params.BlockHash, nil
+	})
+
+	handle(s, daemon.GetAccountRegistrationTopoheight, func(params addressParams) (interface{}, *rpc.RPCError) {
+		topoheight, ok := s.registrations[params.Address]
+		if !ok {
+			return nil, errNotFound("Account not found: %s", params.Address)
+		}
+
+		return topoheight, nil
+	})
+
+	handle(s, daemon.IsAccountRegistered, func(params daemon.IsAccountRegisteredParams) (interface{}, *rpc.RPCError) {
+		topoheight, ok := s.registrations[params.Address]
+		if ok && params.InStableHeight {
+			ok = topoheight <= s.stableTopoheight()
+		}
+
+		return ok, nil
+	})
+
+	handle(s, daemon.GetDifficulty, func(noParams) (interface{}, *rpc.RPCError) {
+		difficulty, ok := new(big.Int).SetString(s.Difficulty, 10)
+		if !ok {
+			return nil, rpctest.NewError(rpc.InternalErrorCode, "invalid difficulty %s", s.Difficulty)
+		}
+
+		seconds := s.BlockTimeTarget / 1000
+		if seconds == 0 {
+			seconds = 1
+		}
+
+		hashrate := new(big.Int).Div(difficulty, new(big.Int).SetUint64(seconds))
+		return daemon.GetDifficultyResult{
+			Difficulty:        difficulty.String(),
+			Hashrate:          hashrate.String(),
+			HashrateFormatted: fmt.Sprintf("%s H/s", hashrate.String()),
+		}, nil
+	})
+
+	handle(s, daemon.ValidateAddress, func(params daemon.ValidateAddressParams) (interface{}, *rpc.RPCError) {
+		addr, err := address.NewAddressFromString(params.Address)
+		if err != nil || addr == nil {
+			return daemon.ValidateAddressResult{}, nil
+		}
+
+		valid := addr.IsMainnet() == (s.Network == "Mainnet")
+		if addr.IsIntegrated() && !params.AllowIntegrated {
+			valid = false
+		}
+
+		return daemon.ValidateAddressResult{IsIntegrated: addr.IsIntegrated(), IsValid: valid}, nil
+	})
+
+	handle(s, daemon.ExtractKeyFromAddress, func(params daemon.ExtractKeyFromAddressParams) (interface{}, *rpc.RPCError) {
+		addr, err := address.NewAddressFromString(params.Address)
+		if err != nil || addr == nil {
+			return nil, rpctest.InvalidParams(fmt.Errorf("invalid address"))
+		}
+
+		key := addr.GetPublicKey()
+		if params.AsHex {
+			return hex.EncodeToString(key), nil
+		}
+
+		// keep it as an array of numbers like the daemon instead of base64
+		bytes := make([]int, len(key))
+		for i, b := range key {
+			bytes[i] = int(b)
+		}
+
+		return bytes, nil
+	})
+
+	handle(s, daemon.SplitAddress, func(params daemon.SplitAddressParams) (interface{}, *rpc.RPCError) {
+		addr, err := address.NewAddressFromString(params.Address)
+		if err != nil || addr == nil {
+			return nil, rpctest.InvalidParams(fmt.Errorf("invalid address"))
+		}
+
+		if !addr.IsIntegrated() {
+			return nil, rpctest.InvalidParams(fmt.Errorf("address is not integrated"))
+		}
+
+		integratedData := *addr.GetExtraData()
+		addr.ClearExtraData()
+		plain, err := addr.Format()
+		if err != nil {
+			return nil, rpctest.NewError(rpc.InternalErrorCode, "%s", err)
+		}
+
+		return daemon.SplitAddressResult{Address: plain, IntegratedData: integratedData}, nil
+	})
+}
+
+// Header of the next block mined by miner, with the top block as tip and the mempool transactions.
+func (s *Server) template(miner *address.Address) block.Header {
+	top := s.top()
+	tip, _ := block.NewHashFromString(top.Hash)
+	header := block.Header{
+		Height:    top.Height + 1,
+		Timestamp: top.Timestamp + s.BlockTimeTarget,
+		Tips:      []block.Hash{tip},
+	}
+	copy(header.Miner[:], miner.GetPublicKey())
+
+	for _, txHash := range s.mempool {
+		hash, err := block.NewHashFromString(txHash)
+		if err == nil {
+			header.TxsHashes = append(header.TxsHashes, hash)
+		}
+	}
+
+	return header
+}
+
+// Block with the fields of a submitted header. Its tips must be known blocks.
+func (s *Server) blockFromHeader(header block.Header) (daemon.Block, *rpc.RPCError) {
+	hrp := address.TestnetPrefixAddress
+	if s.Network == "Mainnet" {
+		hrp = address.PrefixAddress
+	}
+
+	miner, err := address.NewAddressFromData(append(header.Miner[:], 0), hrp)
+	if err != nil {
+		return daemon.Block{}, rpctest.InvalidParams(err)
+	}
+
+	minerAddr, err := miner.Format()
+	if err != nil {
+		return daemon.Block{}, rpctest.InvalidParams(err)
+	}
+
+	b := daemon.Block{
+		Version:    uint64(header.Version),
+		Height:     header.Height,
+		Timestamp:  header.Timestamp,
+		Nonce:      header.Nonce,
+		ExtraNonce: hex.EncodeToString(header.ExtraNonce[:]),
+		Miner:      minerAddr,
+		Tips:       []string{},
+		TxsHashes:  []string{},
+	}
+
+	defer s.mutex.Unlock()
+	s.mutex.Lock()
+
+	for _, tip := range header.Tips {
+		_, ok := s.blockByHash(tip.String())
+		if !ok {
+			return daemon.Block{}, rpctest.InvalidParams(fmt.Errorf("unknown tip %s", tip))
+		}
+
+		b.Tips = append(b.Tips, tip.String())
+	}
+
+	for _, txHash := range header.TxsHashes {
+		b.TxsHashes = append(b.TxsHashes, txHash.String())
+	}
+
+	return b, nil
+}
+
+func (s *Server) blockByHash(hash string) (daemon.Block, bool) {
+	for _, block := range s.blocks {
+		if block.Hash == hash {
+			return block, true
+		}
+	}
+
+	return daemon.Block{}, false
+}
+
+func (s *Server) blocksRange(start uint64, end uint64) ([]daemon.Block, *rpc.RPCError) {
+	if end < start || end-start >= MAX_BLOCKS || end > s.topoheight() {
+		return nil, rpctest.InvalidParams(fmt.Errorf("invalid range %d-%d", start, end))
+	}
+
+	return append([]daemon.Block{}, s.blocks[start:end+1]...), nil
+}
+
+// Latest balance version at or below topoheight, or exactly at topoheight if exact is set.
+func (s *Server) balanceAt(addr string, asset string, topoheight uint64, exact bool) (version versionedBalance, ok bool) {
+	for _, v := range s.balances[addr][asset] {
+		if v.topoheight > topoheight {
+			break
+		}
+
+		if !exact || v.topoheight == topoheight {
+			version = v
+			ok = true
+		}
+	}
+
+	return
+}
+
+func inTopoheightRange(topoheight uint64, params daemon.GetAccountsParams) bool {
+	if topoheight < params.MinimumTopoheight {
+		return false
+	}
+
+	return params.MaximumTopoheight == 0 || topoheight <= params.MaximumTopoheight
+}
+
+func paginate[T any](items []T, params daemon.GetAccountsParams) []T {
+	if params.Skip >= uint64(len(items)) {
+		return []T{}
+	}
+
+	items = items[params.Skip:]
+	if params.Maximum > 0 && params.Maximum < uint64(len(items)) {
+		items = items[:params.Maximum]
+	}
+
+	return items
+}
+
+func sortedKeys[T any](m map[string]T) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+	return keys
+}
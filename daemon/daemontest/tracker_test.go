@@ -0,0 +1,203 @@
+package daemontest
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/xelis-project/xelis-go-sdk/daemon"
+	"github.com/xelis-project/xelis-go-sdk/rpc"
+)
+
+func nextStatus(t *testing.T, statuses <-chan daemon.TxStatus) daemon.TxStatus {
+	select {
+	case status, ok := <-statuses:
+		if !ok {
+			t.Fatal("statuses channel closed")
+		}
+		return status
+	case <-time.After(5 * time.Second):
+		t.Fatal("status was not received")
+	}
+
+	return daemon.TxStatus{}
+}
+
+func TestTxTracker(t *testing.T) {
+	server, ws, clients := useServer(t)
+	tracker := daemon.NewTxTracker(clients["rpc"], ws, daemon.TxTrackerOptions{
+		PollInterval: 20 * time.Millisecond,
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go tracker.Run(ctx)
+
+	tx := server.AddTransaction(daemon.Transaction{})
+	statuses := tracker.Track(tx.Hash)
+
+	status := nextStatus(t, statuses)
+	if status.State != daemon.TxInMempool {
+		t.Fatalf("Expected in mempool, got %+v", status)
+	}
+
+	orphan := server.MineBlock(tx.Hash)
+	status = nextStatus(t, statuses)
+	if status.State != daemon.TxExecuted || status.BlockHash != orphan.Hash {
+		t.Fatalf("Expected executed in %s, got %+v", orphan.Hash, status)
+	}
+
+	server.ReorgFrom(*orphan.Topoheight, daemon.Block{})
+	status = nextStatus(t, statuses)
+	if status.State != daemon.TxOrphaned || status.BlockHash != orphan.Hash {
+		t.Fatalf("Expected orphaned from %s, got %+v", orphan.Hash, status)
+	}
+
+	block := server.MineBlock(tx.Hash)
+	status = nextStatus(t, statuses)
+	if status.State != daemon.TxExecuted || status.BlockHash != block.Hash {
+		t.Fatalf("Expected executed in %s, got %+v", block.Hash, status)
+	}
+
+	for i := 0; i < STABLE_LIMIT; i++ {
+		server.MineBlock()
+	}
+
+	status = nextStatus(t, statuses)
+	if status.State != daemon.TxStable || status.Topoheight != *block.Topoheight {
+		t.Fatalf("Expected stable at topoheight %d, got %+v", *block.Topoheight, status)
+	}
+
+	_, ok := <-statuses
+	if ok {
+		t.Error("Expected statuses channel to be closed")
+	}
+}
+
+func TestTxTrackerUntrack(t *testing.T) {
+	server, _, clients := useServer(t)
+	tracker := daemon.NewTxTracker(clients["ws"], nil, daemon.TxTrackerOptions{
+		PollInterval: 20 * time.Millisecond,
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go tracker.Run(ctx)
+
+	tx := server.AddTransaction(daemon.Transaction{})
+	statuses := tracker.Track(tx.Hash)
+	nextStatus(t, statuses)
+	tracker.Untrack(tx.Hash)
+
+	select {
+	case _, ok := <-statuses:
+		if ok {
+			t.Error("Expected statuses channel to be closed")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("statuses channel was not closed")
+	}
+}
+
+// A transaction unknown to the daemon is not an error, it may not be propagated yet.
+func TestTxTrackerUnknownTransaction(t *testing.T) {
+	_, _, clients := useServer(t)
+	errs := make(chan error, 10)
+	tracker := daemon.NewTxTracker(clients["rpc"], nil, daemon.TxTrackerOptions{
+		PollInterval: 10 * time.Millisecond,
+		OnError: func(err error) {
+			errs <- err
+		},
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	tracker.Track("0000000000000000000000000000000000000000000000000000000000000001")
+	tracker.Run(ctx)
+
+	if len(errs) != 0 {
+		t.Errorf("Unexpected error %v", <-errs)
+	}
+}
+
+type failingClient struct {
+	daemon.Client
+	err error
+}
+
+func (c failingClient) GetTransactionContext(ctx context.Context, hash string) (daemon.Transaction, error) {
+	return daemon.Transaction{}, c.err
+}
+
+// Internal errors other than an unknown transaction are reported.
+func TestTxTrackerDaemonError(t *testing.T) {
+	_, _, clients := useServer(t)
+	failure := &rpc.RPCError{Code: rpc.InternalErrorCode, Message: "Error while reading from storage"}
+	errs := make(chan error, 10)
+	tracker := daemon.NewTxTracker(failingClient{Client: clients["rpc"], err: failure}, nil, daemon.TxTrackerOptions{
+		PollInterval: 10 * time.Millisecond,
+		OnError: func(err error) {
+			errs <- err
+		},
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go tracker.Run(ctx)
+
+	tracker.Track("0000000000000000000000000000000000000000000000000000000000000001")
+	select {
+	case err := <-errs:
+		if !errors.Is(err, failure) {
+			t.Errorf("Unexpected error %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("daemon error was not reported")
+	}
+}
+
+// Stopping the tracker must not remove the other listeners of the shared WebSocket.
+func TestTxTrackerSharedWebSocket(t *testing.T) {
+	server, ws, clients := useServer(t)
+
+	blocks := make(chan daemon.Block, 100)
+	err := ws.NewBlockFunc(func(block daemon.Block, err error) {
+		if err == nil {
+			blocks <- block
+		}
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tracker := daemon.NewTxTracker(clients["rpc"], ws, daemon.TxTrackerOptions{})
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan bool)
+	go func() {
+		tracker.Run(ctx)
+		close(done)
+	}()
+
+	tx := server.AddTransaction(daemon.Transaction{})
+	statuses := tracker.Track(tx.Hash)
+	if status := nextStatus(t, statuses); status.State != daemon.TxInMempool {
+		t.Fatalf("Expected in mempool, got %+v", status)
+	}
+
+	cancel()
+	<-done
+
+	block := server.MineBlock()
+	for {
+		select {
+		case received := <-blocks:
+			if received.Hash == block.Hash {
+				return
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatal("new_block was not received after the tracker stopped")
+		}
+	}
+}
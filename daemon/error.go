@@ -0,0 +1,15 @@
+package daemon
+
+import "github.com/xelis-project/xelis-go-sdk/rpc"
+
+// The daemon reports these failures with the internal error code, so they are matched on the message too.
+// Use them with errors.Is on errors returned by RPC and WebSocket.
+var (
+	ErrBlockNotFound       = &rpc.RPCError{Code: rpc.InternalErrorCode, Message: "block not found"}
+	ErrTransactionNotFound = &rpc.RPCError{Code: rpc.InternalErrorCode, Message: "transaction not found"}
+	ErrAccountNotFound     = &rpc.RPCError{Code: rpc.InternalErrorCode, Message: "account not found"}
+	ErrBalanceNotFound     = &rpc.RPCError{Code: rpc.InternalErrorCode, Message: "balance not found"}
+	ErrNonceNotFound       = &rpc.RPCError{Code: rpc.InternalErrorCode, Message: "nonce not found"}
+	ErrAssetNotFound       = &rpc.RPCError{Code: rpc.InternalErrorCode, Message: "asset not found"}
+	ErrTxAlreadyInMempool  = &rpc.RPCError{Code: rpc.InternalErrorCode, Message: "already in mempool"}
+)
@@ -0,0 +1,326 @@
+package daemon
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/xelis-project/xelis-go-sdk/config"
+)
+
+// Last block applied by a Follower.
+type FollowerCursor struct {
+	Topoheight uint64
+	Hash       string
+}
+
+type FollowerOptions struct {
+	// Last block already applied, to resume following. Nil starts from the genesis block.
+	Cursor *FollowerCursor
+	// Called for every block in increasing topoheight order.
+	OnApply func(block Block) error
+	// Called for every applied block whose topoheight was reordered, from the highest topoheight to the lowest.
+	// When resuming from Cursor, the reverted cursor block only has Hash and Topoheight set.
+	OnRevert func(block Block) error
+	// Interval between two checks of the chain when no event is received. Defaults to 5 seconds.
+	PollInterval time.Duration
+	// Number of applied blocks kept to be reverted. A reorg deeper than this is not detected. Defaults to 64.
+	MaxReorgDepth int
+}
+
+// Follower applies the blocks of the DAG in topoheight order and reverts them when their topoheight is reordered.
+// It backfills with GetBlocksRangeByTopoheight and checks the applied hashes with GetDAGOrder.
+// Events from the block_ordered subscription only wake it up, so missed events and disconnects are caught up on the next poll.
+type Follower struct {
+	client  Client
+	ws      *WebSocket
+	options FollowerOptions
+
+	mutex     sync.Mutex
+	applied   []Block // contiguous topoheights, the last one is the cursor
+	reorgFrom *uint64 // lowest topoheight reordered by an event
+	wake      chan struct{}
+}
+
+// The WebSocket is optional. Without it the chain is only polled.
+func NewFollower(client Client, ws *WebSocket, options FollowerOptions) *Follower {
+	if options.PollInterval <= 0 {
+		options.PollInterval = 5 * time.Second
+	}
+
+	if options.MaxReorgDepth <= 0 {
+		options.MaxReorgDepth = 64
+	}
+
+	f := &Follower{
+		client:  client,
+		ws:      ws,
+		options: options,
+		wake:    make(chan struct{}, 1),
+	}
+
+	if options.Cursor != nil {
+		topoheight := options.Cursor.Topoheight
+		f.applied = append(f.applied, Block{Hash: options.Cursor.Hash, Topoheight: &topoheight})
+	}
+
+	return f
+}
+
+// Last applied block. The second value is false if no block was applied yet.
+// Hash is empty if every applied block was reverted.
+func (f *Follower) Cursor() (FollowerCursor, bool) {
+	defer f.mutex.Unlock()
+	f.mutex.Lock()
+
+	if len(f.applied) == 0 {
+		return FollowerCursor{}, false
+	}
+
+	last := f.applied[len(f.applied)-1]
+	return FollowerCursor{Topoheight: *last.Topoheight, Hash: last.Hash}, true
+}
+
+// Follow the chain until the context is done or a callback returns an error.
+// Callbacks are called from this goroutine only.
+// Errors from the daemon are retried on the next poll.
+func (f *Follower) Run(ctx context.Context) error {
+	if f.ws != nil {
+		// Our own listener, the other listeners of the event on the same connection are left untouched.
+		listener, err := f.ws.BlockOrderedListener(f.onBlockOrdered)
+		if err != nil {
+			return err
+		}
+		defer listener.Close()
+	}
+
+	ticker := time.NewTicker(f.options.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		err := f.sync(ctx)
+		var callbackErr *callbackError
+		if errors.As(err, &callbackErr) {
+			return callbackErr.err
+		}
+
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-f.wake:
+		case <-ticker.C:
+		}
+	}
+}
+
+func (f *Follower) onBlockOrdered(result BlockOrderedResult, err error) {
+	if err != nil {
+		return
+	}
+
+	f.mutex.Lock()
+	if hash, ok := f.hashAt(result.Topoheight); ok && hash != result.BlockHash {
+		if f.reorgFrom == nil || result.Topoheight < *f.reorgFrom {
+			topoheight := result.Topoheight
+			f.reorgFrom = &topoheight
+		}
+	}
+	f.mutex.Unlock()
+
+	select {
+	case f.wake <- struct{}{}:
+	default:
+	}
+}
+
+// Distinguish errors returned by OnApply and OnRevert from the daemon ones.
+type callbackError struct {
+	err error
+}
+
+func (e *callbackError) Error() string {
+	return e.err.Error()
+}
+
+// Hash of the applied block at topoheight. Must be called with the mutex locked.
+func (f *Follower) hashAt(topoheight uint64) (string, bool) {
+	if len(f.applied) == 0 {
+		return "", false
+	}
+
+	first := *f.applied[0].Topoheight
+	if topoheight < first || topoheight-first >= uint64(len(f.applied)) {
+		return "", false
+	}
+
+	return f.applied[topoheight-first].Hash, true
+}
+
+func (f *Follower) sync(ctx context.Context) error {
+	top, err := f.client.GetTopoheightContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	err = f.checkReorg(ctx, top)
+	if err != nil {
+		return err
+	}
+
+	for {
+		f.mutex.Lock()
+		next := uint64(0)
+		if len(f.applied) > 0 {
+			next = *f.applied[len(f.applied)-1].Topoheight + 1
+		}
+		f.mutex.Unlock()
+
+		if next > top {
+			return nil
+		}
+
+		end := next + config.MAX_BLOCKS_RANGE - 1
+		if end > top {
+			end = top
+		}
+
+		blocks, err := f.client.GetBlocksRangeByTopoheightContext(ctx, GetTopoheightRangeParams{StartTopoheight: next, EndTopoheight: end})
+		if err != nil {
+			return err
+		}
+
+		if len(blocks) == 0 {
+			return nil
+		}
+
+		for _, block := range blocks {
+			err = f.apply(block)
+			if err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func (f *Follower) apply(block Block) error {
+	if f.options.OnApply != nil {
+		err := f.options.OnApply(block)
+		if err != nil {
+			return &callbackError{err: err}
+		}
+	}
+
+	defer f.mutex.Unlock()
+	f.mutex.Lock()
+
+	f.applied = append(f.applied, block)
+	if len(f.applied) > f.options.MaxReorgDepth {
+		f.applied = append([]Block{}, f.applied[len(f.applied)-f.options.MaxReorgDepth:]...)
+	}
+
+	return nil
+}
+
+// Compare the applied hashes with the DAG order and revert every block from the first difference.
+// The last MAX_BLOCKS_RANGE topoheights are always checked, and older ones if an event reported a change.
+func (f *Follower) checkReorg(ctx context.Context, top uint64) error {
+	f.mutex.Lock()
+	if len(f.applied) == 0 {
+		f.mutex.Unlock()
+		return nil
+	}
+
+	first := *f.applied[0].Topoheight
+	cursor := *f.applied[len(f.applied)-1].Topoheight
+
+	from := first
+	if cursor-first >= config.MAX_BLOCKS_RANGE {
+		from = cursor - config.MAX_BLOCKS_RANGE + 1
+	}
+
+	if f.reorgFrom != nil && *f.reorgFrom < from {
+		from = *f.reorgFrom
+		if from < first {
+			from = first
+		}
+	}
+	f.reorgFrom = nil
+	f.mutex.Unlock()
+
+	// Topoheights above the top are not ordered anymore.
+	var fork *uint64
+	to := cursor
+	if top < cursor {
+		fork = new(uint64)
+		*fork = top + 1
+		to = top
+	}
+
+	for start := from; start <= to && (fork == nil || start < *fork); start += config.MAX_BLOCKS_RANGE {
+		end := start + config.MAX_BLOCKS_RANGE - 1
+		if end > to {
+			end = to
+		}
+
+		hashes, err := f.client.GetDAGOrderContext(ctx, GetTopoheightRangeParams{StartTopoheight: start, EndTopoheight: end})
+		if err != nil {
+			return err
+		}
+
+		f.mutex.Lock()
+		for i, hash := range hashes {
+			topoheight := start + uint64(i)
+			applied, ok := f.hashAt(topoheight)
+			if ok && applied != "" && applied != hash {
+				fork = &topoheight
+				break
+			}
+		}
+		f.mutex.Unlock()
+	}
+
+	if fork == nil {
+		return nil
+	}
+
+	return f.revertFrom(*fork)
+}
+
+// Revert every applied block at topoheight or above, from the highest one.
+func (f *Follower) revertFrom(topoheight uint64) error {
+	for {
+		f.mutex.Lock()
+		if len(f.applied) == 0 {
+			f.mutex.Unlock()
+			return nil
+		}
+
+		last := f.applied[len(f.applied)-1]
+		f.mutex.Unlock()
+
+		if *last.Topoheight < topoheight {
+			return nil
+		}
+
+		if f.options.OnRevert != nil && last.Hash != "" {
+			err := f.options.OnRevert(last)
+			if err != nil {
+				return &callbackError{err: err}
+			}
+		}
+
+		f.mutex.Lock()
+		f.applied = f.applied[:len(f.applied)-1]
+		if len(f.applied) == 0 && *last.Topoheight > 0 {
+			// Keep the topoheight to continue from there, the hash is unknown.
+			previous := *last.Topoheight - 1
+			f.applied = append(f.applied, Block{Topoheight: &previous})
+		}
+		f.mutex.Unlock()
+	}
+}
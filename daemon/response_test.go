@@ -0,0 +1,122 @@
+package daemon
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/gorilla/websocket"
+)
+
+// Results as sent by the daemon, has_nonce and has_balance reply with {"exist": bool}.
+var rawResults = map[string]string{
+	HasNonce:         `{"exist":true}`,
+	HasBalance:       `{"exist":true}`,
+	GetStableBalance: `{"stable_topoheight":5,"stable_block_hash":"00"}`,
+}
+
+// Serve rawResults over HTTP and WebSocket and record the params of every call.
+func useRawServer(t *testing.T) (endpoint string, paramsOf func(method string) json.RawMessage) {
+	var mutex sync.Mutex
+	params := make(map[string]json.RawMessage)
+	paramsOf = func(method string) json.RawMessage {
+		defer mutex.Unlock()
+		mutex.Lock()
+		return params[method]
+	}
+
+	reply := func(data []byte) []byte {
+		var req struct {
+			ID     json.RawMessage `json:"id"`
+			Method string          `json:"method"`
+			Params json.RawMessage `json:"params"`
+		}
+
+		err := json.Unmarshal(data, &req)
+		if err != nil {
+			t.Error(err)
+		}
+
+		mutex.Lock()
+		params[req.Method] = req.Params
+		mutex.Unlock()
+
+		return []byte(`{"jsonrpc":"2.0","id":` + string(req.ID) + `,"result":` + rawResults[req.Method] + `}`)
+	}
+
+	upgrader := websocket.Upgrader{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !websocket.IsWebSocketUpgrade(r) {
+			data, _ := io.ReadAll(r.Body)
+			w.Header().Set("Content-Type", "application/json")
+			w.Write(reply(data))
+			return
+		}
+
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		for {
+			_, data, err := conn.ReadMessage()
+			if err != nil {
+				return
+			}
+
+			conn.WriteMessage(websocket.TextMessage, reply(data))
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	return server.URL, paramsOf
+}
+
+func TestRawResponses(t *testing.T) {
+	endpoint, paramsOf := useRawServer(t)
+
+	rpcClient, err := NewRPC(context.Background(), endpoint)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ws, err := NewWebSocket("ws" + strings.TrimPrefix(endpoint, "http"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ws.Close()
+
+	balanceParams := GetBalanceParams{Address: "addr", Asset: "asset"}
+	for name, client := range map[string]interface {
+		HasNonce(addr string) (bool, error)
+		HasBalance(params GetBalanceParams) (bool, error)
+		GetStableBalance(params GetBalanceParams) (GetStableBalanceResult, error)
+	}{"rpc": rpcClient, "ws": ws} {
+		hasNonce, err := client.HasNonce("addr")
+		if err != nil || !hasNonce {
+			t.Errorf("%s: expected nonce, got %v", name, err)
+		}
+
+		hasBalance, err := client.HasBalance(balanceParams)
+		if err != nil || !hasBalance {
+			t.Errorf("%s: expected balance, got %v", name, err)
+		}
+
+		stable, err := client.GetStableBalance(balanceParams)
+		if err != nil || stable.StableTopoheight != 5 {
+			t.Errorf("%s: unexpected stable balance %+v, %v", name, stable, err)
+		}
+
+		var sent GetBalanceParams
+		json.Unmarshal(paramsOf(GetStableBalance), &sent)
+		if sent != balanceParams {
+			t.Errorf("%s: expected params %+v, got %s", name, balanceParams, paramsOf(GetStableBalance))
+		}
+	}
+}
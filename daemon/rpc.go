@@ -6,6 +6,7 @@ import (
 
 	"github.com/creachadair/jrpc2"
 	"github.com/creachadair/jrpc2/jhttp"
+	"github.com/xelis-project/xelis-go-sdk/rpc"
 )
 
 type RPC struct {
@@ -30,250 +31,444 @@ func NewRPC(ctx context.Context, url string) (*RPC, error) {
 	return daemon, nil
 }
 
+// Same as Client.CallResult, with server errors returned as *rpc.RPCError like the WebSocket client.
+func (d *RPC) callResult(ctx context.Context, method string, params interface{}, result interface{}) error {
+	err := d.Client.CallResult(ctx, method, params, result)
+	return rpc.FromJRPC2Error(err)
+}
+
 func (d *RPC) GetVersion() (version string, err error) {
-	err = d.Client.CallResult(d.ctx, string(GetVersion), nil, &version)
+	return d.GetVersionContext(d.ctx)
+}
+
+func (d *RPC) GetVersionContext(ctx context.Context) (version string, err error) {
+	err = d.callResult(ctx, string(GetVersion), nil, &version)
 	return
 }
 
 func (d *RPC) GetInfo() (result GetInfoResult, err error) {
-	err = d.Client.CallResult(d.ctx, string(GetInfo), nil, &result)
+	return d.GetInfoContext(d.ctx)
+}
+
+func (d *RPC) GetInfoContext(ctx context.Context) (result GetInfoResult, err error) {
+	err = d.callResult(ctx, string(GetInfo), nil, &result)
 	return
 }
 
 func (d *RPC) GetHeight() (height uint64, err error) {
-	err = d.Client.CallResult(d.ctx, string(GetHeight), nil, &height)
+	return d.GetHeightContext(d.ctx)
+}
+
+func (d *RPC) GetHeightContext(ctx context.Context) (height uint64, err error) {
+	err = d.callResult(ctx, string(GetHeight), nil, &height)
 	return
 }
 
 func (d *RPC) GetTopoheight() (topoheight uint64, err error) {
-	err = d.Client.CallResult(d.ctx, string(GetTopoHeight), nil, &topoheight)
+	return d.GetTopoheightContext(d.ctx)
+}
+
+func (d *RPC) GetTopoheightContext(ctx context.Context) (topoheight uint64, err error) {
+	err = d.callResult(ctx, string(GetTopoHeight), nil, &topoheight)
 	return
 }
 
 func (d *RPC) GetStableHeight() (stableheight uint64, err error) {
-	err = d.Client.CallResult(d.ctx, string(GetStableHeight), nil, &stableheight)
+	return d.GetStableHeightContext(d.ctx)
+}
+
+func (d *RPC) GetStableHeightContext(ctx context.Context) (stableheight uint64, err error) {
+	err = d.callResult(ctx, string(GetStableHeight), nil, &stableheight)
 	return
 }
 
 func (d *RPC) GetStableTopoheight() (topoheight uint64, err error) {
-	err = d.Client.CallResult(d.ctx, string(GetStableTopoheight), nil, &topoheight)
+	return d.GetStableTopoheightContext(d.ctx)
+}
+
+func (d *RPC) GetStableTopoheightContext(ctx context.Context) (topoheight uint64, err error) {
+	err = d.callResult(ctx, string(GetStableTopoheight), nil, &topoheight)
 	return
 }
 
 func (d *RPC) GetStableBalance(params GetBalanceParams) (result GetStableBalanceResult, err error) {
-	err = d.Client.CallResult(d.ctx, string(GetStableBalance), params, &result)
+	return d.GetStableBalanceContext(d.ctx, params)
+}
+
+func (d *RPC) GetStableBalanceContext(ctx context.Context, params GetBalanceParams) (result GetStableBalanceResult, err error) {
+	err = d.callResult(ctx, string(GetStableBalance), params, &result)
 	return
 }
 
 func (d *RPC) GetBlockTemplate(addr string) (result GetBlockTemplateResult, err error) {
+	return d.GetBlockTemplateContext(d.ctx, addr)
+}
+
+func (d *RPC) GetBlockTemplateContext(ctx context.Context, addr string) (result GetBlockTemplateResult, err error) {
 	params := map[string]string{"address": addr}
-	err = d.Client.CallResult(d.ctx, string(GetBlockTemplate), params, &result)
+	err = d.callResult(ctx, string(GetBlockTemplate), params, &result)
 	return
 }
 
 func (d *RPC) GetBlockAtTopoheight(params GetBlockAtTopoheightParams) (block Block, err error) {
-	err = d.Client.CallResult(d.ctx, string(GetBlockAtTopoheight), params, &block)
+	return d.GetBlockAtTopoheightContext(d.ctx, params)
+}
+
+func (d *RPC) GetBlockAtTopoheightContext(ctx context.Context, params GetBlockAtTopoheightParams) (block Block, err error) {
+	err = d.callResult(ctx, string(GetBlockAtTopoheight), params, &block)
 	return
 }
 
 func (d *RPC) GetBlocksAtHeight(params GetBlocksAtHeightParams) (blocks []Block, err error) {
-	err = d.Client.CallResult(d.ctx, string(GetBlocksAtHeight), params, &blocks)
+	return d.GetBlocksAtHeightContext(d.ctx, params)
+}
+
+func (d *RPC) GetBlocksAtHeightContext(ctx context.Context, params GetBlocksAtHeightParams) (blocks []Block, err error) {
+	err = d.callResult(ctx, string(GetBlocksAtHeight), params, &blocks)
 	return
 }
 
 func (d *RPC) GetBlockByHash(params GetBlockByHashParams) (block Block, err error) {
-	err = d.Client.CallResult(d.ctx, string(GetBlockByHash), params, &block)
+	return d.GetBlockByHashContext(d.ctx, params)
+}
+
+func (d *RPC) GetBlockByHashContext(ctx context.Context, params GetBlockByHashParams) (block Block, err error) {
+	err = d.callResult(ctx, string(GetBlockByHash), params, &block)
 	return
 }
 
 func (d *RPC) GetTopBlock(params GetTopBlockParams) (block Block, err error) {
-	err = d.Client.CallResult(d.ctx, string(GetTopBlock), params, &block)
+	return d.GetTopBlockContext(d.ctx, params)
+}
+
+func (d *RPC) GetTopBlockContext(ctx context.Context, params GetTopBlockParams) (block Block, err error) {
+	err = d.callResult(ctx, string(GetTopBlock), params, &block)
 	return
 }
 
 func (d *RPC) GetNonce(addr string) (nonce GetNonceResult, err error) {
+	return d.GetNonceContext(d.ctx, addr)
+}
+
+func (d *RPC) GetNonceContext(ctx context.Context, addr string) (nonce GetNonceResult, err error) {
 	params := map[string]string{"address": addr}
-	err = d.Client.CallResult(d.ctx, string(GetNonce), params, &nonce)
+	err = d.callResult(ctx, string(GetNonce), params, &nonce)
 	return
 }
 
 func (d *RPC) HasNonce(addr string) (hasNonce bool, err error) {
+	return d.HasNonceContext(d.ctx, addr)
+}
+
+func (d *RPC) HasNonceContext(ctx context.Context, addr string) (hasNonce bool, err error) {
 	params := map[string]string{"address": addr}
 	var result map[string]bool
-	err = d.Client.CallResult(d.ctx, string(HasNonce), params, &result)
+	err = d.callResult(ctx, string(HasNonce), params, &result)
 	hasNonce = result["exist"]
 	return
 }
 
 func (d *RPC) GetNonceAtTopoheight(params GetNonceAtTopoheightParams) (nonce VersionedNonce, err error) {
-	err = d.Client.CallResult(d.ctx, string(GetNonceAtTopoheight), params, &nonce)
+	return d.GetNonceAtTopoheightContext(d.ctx, params)
+}
+
+func (d *RPC) GetNonceAtTopoheightContext(ctx context.Context, params GetNonceAtTopoheightParams) (nonce VersionedNonce, err error) {
+	err = d.callResult(ctx, string(GetNonceAtTopoheight), params, &nonce)
 	return
 }
 
 func (d *RPC) GetBalance(params GetBalanceParams) (balance GetBalanceResult, err error) {
-	err = d.Client.CallResult(d.ctx, string(GetBalance), params, &balance)
+	return d.GetBalanceContext(d.ctx, params)
+}
+
+func (d *RPC) GetBalanceContext(ctx context.Context, params GetBalanceParams) (balance GetBalanceResult, err error) {
+	err = d.callResult(ctx, string(GetBalance), params, &balance)
 	return
 }
 
 func (d *RPC) HasBalance(params GetBalanceParams) (hasBalance bool, err error) {
+	return d.HasBalanceContext(d.ctx, params)
+}
+
+func (d *RPC) HasBalanceContext(ctx context.Context, params GetBalanceParams) (hasBalance bool, err error) {
 	var result map[string]bool
-	err = d.Client.CallResult(d.ctx, string(HasBalance), params, &result)
-	hasBalance = result["exists"]
+	err = d.callResult(ctx, string(HasBalance), params, &result)
+	hasBalance = result["exist"]
 	return
 }
 
 func (d *RPC) GetBalanceAtTopoheight(params GetBalanceAtTopoheightParams) (balance VersionedBalance, err error) {
-	err = d.Client.CallResult(d.ctx, string(GetBalanceAtTopoheight), params, &balance)
+	return d.GetBalanceAtTopoheightContext(d.ctx, params)
+}
+
+func (d *RPC) GetBalanceAtTopoheightContext(ctx context.Context, params GetBalanceAtTopoheightParams) (balance VersionedBalance, err error) {
+	err = d.callResult(ctx, string(GetBalanceAtTopoheight), params, &balance)
 	return
 }
 
 func (d *RPC) GetAsset(assetId string) (asset Asset, err error) {
+	return d.GetAssetContext(d.ctx, assetId)
+}
+
+func (d *RPC) GetAssetContext(ctx context.Context, assetId string) (asset Asset, err error) {
 	params := map[string]string{"asset": assetId}
-	err = d.Client.CallResult(d.ctx, string(GetAsset), params, &asset)
+	err = d.callResult(ctx, string(GetAsset), params, &asset)
 	return
 }
 
 func (d *RPC) GetAssets(params GetAssetsParams) (assets []AssetWithData, err error) {
-	err = d.Client.CallResult(d.ctx, string(GetAssets), params, &assets)
+	return d.GetAssetsContext(d.ctx, params)
+}
+
+func (d *RPC) GetAssetsContext(ctx context.Context, params GetAssetsParams) (assets []AssetWithData, err error) {
+	err = d.callResult(ctx, string(GetAssets), params, &assets)
 	return
 }
 
 func (d *RPC) CountAssets() (count uint64, err error) {
-	err = d.Client.CallResult(d.ctx, string(CountAssets), nil, &count)
+	return d.CountAssetsContext(d.ctx)
+}
+
+func (d *RPC) CountAssetsContext(ctx context.Context) (count uint64, err error) {
+	err = d.callResult(ctx, string(CountAssets), nil, &count)
 	return
 }
 
 func (d *RPC) CountTransactions() (count uint64, err error) {
-	err = d.Client.CallResult(d.ctx, string(CountTransactions), nil, &count)
+	return d.CountTransactionsContext(d.ctx)
+}
+
+func (d *RPC) CountTransactionsContext(ctx context.Context) (count uint64, err error) {
+	err = d.callResult(ctx, string(CountTransactions), nil, &count)
 	return
 }
 
 func (d *RPC) CountAccounts() (count uint64, err error) {
-	err = d.Client.CallResult(d.ctx, string(CountAccounts), nil, &count)
+	return d.CountAccountsContext(d.ctx)
+}
+
+func (d *RPC) CountAccountsContext(ctx context.Context) (count uint64, err error) {
+	err = d.callResult(ctx, string(CountAccounts), nil, &count)
 	return
 }
 
 func (d *RPC) GetTips() (tips []string, err error) {
-	err = d.Client.CallResult(d.ctx, string(GetTips), nil, &tips)
+	return d.GetTipsContext(d.ctx)
+}
+
+func (d *RPC) GetTipsContext(ctx context.Context) (tips []string, err error) {
+	err = d.callResult(ctx, string(GetTips), nil, &tips)
 	return
 }
 
 func (d *RPC) P2PStatus() (status P2PStatusResult, err error) {
-	err = d.Client.CallResult(d.ctx, string(P2PStatus), nil, &status)
+	return d.P2PStatusContext(d.ctx)
+}
+
+func (d *RPC) P2PStatusContext(ctx context.Context) (status P2PStatusResult, err error) {
+	err = d.callResult(ctx, string(P2PStatus), nil, &status)
 	return
 }
 
 func (d *RPC) GetDAGOrder(params GetTopoheightRangeParams) (hashes []string, err error) {
-	err = d.Client.CallResult(d.ctx, string(GetDAGOrder), params, &hashes)
+	return d.GetDAGOrderContext(d.ctx, params)
+}
+
+func (d *RPC) GetDAGOrderContext(ctx context.Context, params GetTopoheightRangeParams) (hashes []string, err error) {
+	err = d.callResult(ctx, string(GetDAGOrder), params, &hashes)
 	return
 }
 
 func (d *RPC) SubmitBlock(params SubmitBlockParams) (result bool, err error) {
-	err = d.Client.CallResult(d.ctx, string(SubmitBlock), params, &result)
+	return d.SubmitBlockContext(d.ctx, params)
+}
+
+func (d *RPC) SubmitBlockContext(ctx context.Context, params SubmitBlockParams) (result bool, err error) {
+	err = d.callResult(ctx, string(SubmitBlock), params, &result)
 	return
 }
 
 func (d *RPC) SubmitTransaction(data string) (result bool, err error) {
+	return d.SubmitTransactionContext(d.ctx, data)
+}
+
+func (d *RPC) SubmitTransactionContext(ctx context.Context, data string) (result bool, err error) {
 	params := map[string]string{"data": data}
-	err = d.Client.CallResult(d.ctx, string(SubmitTransaction), params, &result)
+	err = d.callResult(ctx, string(SubmitTransaction), params, &result)
 	return
 }
 
 func (d *RPC) GetMempool() (txs []Transaction, err error) {
-	err = d.Client.CallResult(d.ctx, string(GetMempool), nil, &txs)
+	return d.GetMempoolContext(d.ctx)
+}
+
+func (d *RPC) GetMempoolContext(ctx context.Context) (txs []Transaction, err error) {
+	err = d.callResult(ctx, string(GetMempool), nil, &txs)
 	return
 }
 
 func (d *RPC) GetTransaction(hash string) (tx Transaction, err error) {
+	return d.GetTransactionContext(d.ctx, hash)
+}
+
+func (d *RPC) GetTransactionContext(ctx context.Context, hash string) (tx Transaction, err error) {
 	params := map[string]string{"hash": hash}
-	err = d.Client.CallResult(d.ctx, string(GetTransaction), params, &tx)
+	err = d.callResult(ctx, string(GetTransaction), params, &tx)
 	return
 }
 
 func (d *RPC) GetTransactions(params GetTransactionsParams) (txs []Transaction, err error) {
-	err = d.Client.CallResult(d.ctx, string(GetTransactions), params, &txs)
+	return d.GetTransactionsContext(d.ctx, params)
+}
+
+func (d *RPC) GetTransactionsContext(ctx context.Context, params GetTransactionsParams) (txs []Transaction, err error) {
+	err = d.callResult(ctx, string(GetTransactions), params, &txs)
 	return
 }
 
 func (d *RPC) GetBlocksRangeByTopoheight(params GetTopoheightRangeParams) (blocks []Block, err error) {
-	err = d.Client.CallResult(d.ctx, string(GetBlocksRangeByTopoheight), params, &blocks)
+	return d.GetBlocksRangeByTopoheightContext(d.ctx, params)
+}
+
+func (d *RPC) GetBlocksRangeByTopoheightContext(ctx context.Context, params GetTopoheightRangeParams) (blocks []Block, err error) {
+	err = d.callResult(ctx, string(GetBlocksRangeByTopoheight), params, &blocks)
 	return
 }
 
 func (d *RPC) GetBlocksRangeByHeight(params GetHeightRangeParams) (blocks []Block, err error) {
-	err = d.Client.CallResult(d.ctx, string(GetBlocksRangeByHeight), params, &blocks)
+	return d.GetBlocksRangeByHeightContext(d.ctx, params)
+}
+
+func (d *RPC) GetBlocksRangeByHeightContext(ctx context.Context, params GetHeightRangeParams) (blocks []Block, err error) {
+	err = d.callResult(ctx, string(GetBlocksRangeByHeight), params, &blocks)
 	return
 }
 
 func (d *RPC) GetAccounts(params GetAccountsParams) (addresses []string, err error) {
-	err = d.Client.CallResult(d.ctx, string(GetAccounts), params, &addresses)
+	return d.GetAccountsContext(d.ctx, params)
+}
+
+func (d *RPC) GetAccountsContext(ctx context.Context, params GetAccountsParams) (addresses []string, err error) {
+	err = d.callResult(ctx, string(GetAccounts), params, &addresses)
 	return
 }
 
 func (d *RPC) GetAccountHistory(addr string) (history []AccountHistory, err error) {
+	return d.GetAccountHistoryContext(d.ctx, addr)
+}
+
+func (d *RPC) GetAccountHistoryContext(ctx context.Context, addr string) (history []AccountHistory, err error) {
 	params := map[string]string{"address": addr}
-	err = d.Client.CallResult(d.ctx, string(GetAccountHistory), params, &history)
+	err = d.callResult(ctx, string(GetAccountHistory), params, &history)
 	return
 }
 
 func (d *RPC) GetAccountAssets(addr string) (assets []string, err error) {
+	return d.GetAccountAssetsContext(d.ctx, addr)
+}
+
+func (d *RPC) GetAccountAssetsContext(ctx context.Context, addr string) (assets []string, err error) {
 	params := map[string]string{"address": addr}
-	err = d.Client.CallResult(d.ctx, string(GetAccountAssets), params, &assets)
+	err = d.callResult(ctx, string(GetAccountAssets), params, &assets)
 	return
 }
 
 func (d *RPC) GetPeers() (result GetPeersResult, err error) {
-	err = d.Client.CallResult(d.ctx, string(GetPeers), nil, &result)
+	return d.GetPeersContext(d.ctx)
+}
+
+func (d *RPC) GetPeersContext(ctx context.Context) (result GetPeersResult, err error) {
+	err = d.callResult(ctx, string(GetPeers), nil, &result)
 	return
 }
 
 func (d *RPC) GetDevFeeThresholds() (fees []Fee, err error) {
-	err = d.Client.CallResult(d.ctx, string(GetDevFeeThresholds), nil, &fees)
+	return d.GetDevFeeThresholdsContext(d.ctx)
+}
+
+func (d *RPC) GetDevFeeThresholdsContext(ctx context.Context) (fees []Fee, err error) {
+	err = d.callResult(ctx, string(GetDevFeeThresholds), nil, &fees)
 	return
 }
 
 func (d *RPC) GetSizeOnDisk() (sizeOnDisk SizeOnDisk, err error) {
-	err = d.Client.CallResult(d.ctx, string(GetSizeOnDisk), nil, &sizeOnDisk)
+	return d.GetSizeOnDiskContext(d.ctx)
+}
+
+func (d *RPC) GetSizeOnDiskContext(ctx context.Context) (sizeOnDisk SizeOnDisk, err error) {
+	err = d.callResult(ctx, string(GetSizeOnDisk), nil, &sizeOnDisk)
 	return
 }
 
 func (d *RPC) IsTxExecutedInBlock(params IsTxExecutedInBlockParams) (executed bool, err error) {
-	err = d.Client.CallResult(d.ctx, string(IsTxExecutedInBlock), params, &executed)
+	return d.IsTxExecutedInBlockContext(d.ctx, params)
+}
+
+func (d *RPC) IsTxExecutedInBlockContext(ctx context.Context, params IsTxExecutedInBlockParams) (executed bool, err error) {
+	err = d.callResult(ctx, string(IsTxExecutedInBlock), params, &executed)
 	return
 }
 
 func (d *RPC) GetAccountRegistrationTopoheight(addr string) (topoheight uint64, err error) {
+	return d.GetAccountRegistrationTopoheightContext(d.ctx, addr)
+}
+
+func (d *RPC) GetAccountRegistrationTopoheightContext(ctx context.Context, addr string) (topoheight uint64, err error) {
 	params := map[string]string{"address": addr}
-	err = d.Client.CallResult(d.ctx, string(GetAccountRegistrationTopoheight), params, &topoheight)
+	err = d.callResult(ctx, string(GetAccountRegistrationTopoheight), params, &topoheight)
 	return
 }
 
 func (d *RPC) IsAccountRegistered(params IsAccountRegisteredParams) (exists bool, err error) {
-	err = d.Client.CallResult(d.ctx, string(IsAccountRegistered), params, &exists)
+	return d.IsAccountRegisteredContext(d.ctx, params)
+}
+
+func (d *RPC) IsAccountRegisteredContext(ctx context.Context, params IsAccountRegisteredParams) (exists bool, err error) {
+	err = d.callResult(ctx, string(IsAccountRegistered), params, &exists)
 	return
 }
 
 func (d *RPC) GetDifficulty() (result GetDifficultyResult, err error) {
-	err = d.Client.CallResult(d.ctx, string(GetDifficulty), nil, &result)
+	return d.GetDifficultyContext(d.ctx)
+}
+
+func (d *RPC) GetDifficultyContext(ctx context.Context) (result GetDifficultyResult, err error) {
+	err = d.callResult(ctx, string(GetDifficulty), nil, &result)
 	return
 }
 
 func (d *RPC) ValidateAddress(params ValidateAddressParams) (result ValidateAddressResult, err error) {
-	err = d.Client.CallResult(d.ctx, string(ValidateAddress), params, &result)
+	return d.ValidateAddressContext(d.ctx, params)
+}
+
+func (d *RPC) ValidateAddressContext(ctx context.Context, params ValidateAddressParams) (result ValidateAddressResult, err error) {
+	err = d.callResult(ctx, string(ValidateAddress), params, &result)
 	return
 }
 
 func (d *RPC) ExtractKeyFromAddress(params ExtractKeyFromAddressParams) (key interface{}, err error) {
-	err = d.Client.CallResult(d.ctx, string(ExtractKeyFromAddress), params, &key)
+	return d.ExtractKeyFromAddressContext(d.ctx, params)
+}
+
+func (d *RPC) ExtractKeyFromAddressContext(ctx context.Context, params ExtractKeyFromAddressParams) (key interface{}, err error) {
+	err = d.callResult(ctx, string(ExtractKeyFromAddress), params, &key)
 	return
 }
 
 func (d *RPC) GetMinerWork(params GetMinerWorkParams) (result GetMinerWorkResult, err error) {
-	err = d.Client.CallResult(d.ctx, string(GetMinerWork), params, &result)
+	return d.GetMinerWorkContext(d.ctx, params)
+}
+
+func (d *RPC) GetMinerWorkContext(ctx context.Context, params GetMinerWorkParams) (result GetMinerWorkResult, err error) {
+	err = d.callResult(ctx, string(GetMinerWork), params, &result)
 	return
 }
 
 func (d *RPC) SplitAddress(params SplitAddressParams) (result SplitAddressResult, err error) {
-	err = d.Client.CallResult(d.ctx, string(SplitAddress), params, &result)
+	return d.SplitAddressContext(d.ctx, params)
+}
+
+func (d *RPC) SplitAddressContext(ctx context.Context, params SplitAddressParams) (result SplitAddressResult, err error) {
+	err = d.callResult(ctx, string(SplitAddress), params, &result)
 	return
 }
@@ -1,28 +1,38 @@
-package daemon
+package daemon_test
 
 import (
 	"context"
 	"testing"
 
 	"github.com/xelis-project/xelis-go-sdk/config"
+	"github.com/xelis-project/xelis-go-sdk/daemon"
+	"github.com/xelis-project/xelis-go-sdk/daemon/daemontest"
 )
 
 const TESTING_ADDR = "xet:62wnkswt0rmrdd9d2lawgpzuh87fkpmp4gx9j3g4u24yrdkdxgksqnuuucf"
 const MAINNET_ADDR = "xel:as3mgjlevw5ve6k70evzz8lwmsa5p0lgws2d60fulxylnmeqrp9qqukwdfg"
 
-func useRPCTestnet(t *testing.T) (daemon *RPC, ctx context.Context) {
-	ctx = context.Background()
-	daemon, err := NewRPC(ctx, config.TESTNET_NODE_RPC)
-	if err != nil {
-		t.Fatal(err)
+// In-process daemon on the network, with a few blocks and the testing accounts.
+func useServer(t *testing.T, network string) (server *daemontest.Server) {
+	server = daemontest.NewServer()
+	server.Network = network
+	t.Cleanup(server.Close)
+
+	for i := 0; i < 20; i++ {
+		server.MineBlock()
 	}
 
+	server.SetNonce(TESTING_ADDR, 1, 1)
+	server.SetBalance(TESTING_ADDR, config.XELIS_ASSET, 1, daemon.VersionedBalance{BalanceType: "input"})
+	server.AddAccountHistory(TESTING_ADDR, daemon.AccountHistory{Topoheight: 1})
+	server.RegisterAccount(MAINNET_ADDR, 0)
 	return
 }
 
-func useRPCMainnet(t *testing.T) (daemon *RPC, ctx context.Context) {
+func useRPC(t *testing.T, network string) (server *daemontest.Server, client *daemon.RPC, ctx context.Context) {
+	server = useServer(t, network)
 	ctx = context.Background()
-	daemon, err := NewRPC(ctx, config.MAINNET_NODE_RPC)
+	client, err := daemon.NewRPC(ctx, server.RPCEndpoint())
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -30,10 +40,18 @@ func useRPCMainnet(t *testing.T) (daemon *RPC, ctx context.Context) {
 	return
 }
 
+func useRPCTestnet(t *testing.T) (*daemontest.Server, *daemon.RPC, context.Context) {
+	return useRPC(t, "Testnet")
+}
+
+func useRPCMainnet(t *testing.T) (*daemontest.Server, *daemon.RPC, context.Context) {
+	return useRPC(t, "Mainnet")
+}
+
 func TestGetVersion(t *testing.T) {
-	daemon, _ := useRPCTestnet(t)
+	_, client, _ := useRPCTestnet(t)
 
-	version, err := daemon.GetVersion()
+	version, err := client.GetVersion()
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -41,9 +59,9 @@ func TestGetVersion(t *testing.T) {
 }
 
 func TestGetHeight(t *testing.T) {
-	daemon, _ := useRPCTestnet(t)
+	_, client, _ := useRPCTestnet(t)
 
-	height, err := daemon.GetHeight()
+	height, err := client.GetHeight()
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -51,9 +69,9 @@ func TestGetHeight(t *testing.T) {
 }
 
 func TestGetTopoheight(t *testing.T) {
-	daemon, _ := useRPCTestnet(t)
+	_, client, _ := useRPCTestnet(t)
 
-	topoheight, err := daemon.GetTopoheight()
+	topoheight, err := client.GetTopoheight()
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -61,9 +79,9 @@ func TestGetTopoheight(t *testing.T) {
 }
 
 func TestGetStableHeight(t *testing.T) {
-	daemon, _ := useRPCTestnet(t)
+	_, client, _ := useRPCTestnet(t)
 
-	stableheight, err := daemon.GetStableHeight()
+	stableheight, err := client.GetStableHeight()
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -71,9 +89,9 @@ func TestGetStableHeight(t *testing.T) {
 }
 
 func TestGetBlockTemplate(t *testing.T) {
-	daemon, _ := useRPCTestnet(t)
+	_, client, _ := useRPCTestnet(t)
 
-	template, err := daemon.GetBlockTemplate(TESTING_ADDR)
+	template, err := client.GetBlockTemplate(TESTING_ADDR)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -81,9 +99,9 @@ func TestGetBlockTemplate(t *testing.T) {
 }
 
 func TestGetBlockAtTopoheight(t *testing.T) {
-	daemon, _ := useRPCTestnet(t)
+	_, client, _ := useRPCTestnet(t)
 
-	genesisBlock, err := daemon.GetBlockAtTopoheight(GetBlockAtTopoheightParams{Topoheight: 0})
+	genesisBlock, err := client.GetBlockAtTopoheight(daemon.GetBlockAtTopoheightParams{Topoheight: 0})
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -91,9 +109,9 @@ func TestGetBlockAtTopoheight(t *testing.T) {
 }
 
 func TestGetBlocksAtHeight(t *testing.T) {
-	daemon, _ := useRPCTestnet(t)
+	_, client, _ := useRPCTestnet(t)
 
-	blocks, err := daemon.GetBlocksAtHeight(GetBlocksAtHeightParams{Height: 0})
+	blocks, err := client.GetBlocksAtHeight(daemon.GetBlocksAtHeightParams{Height: 0})
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -101,9 +119,10 @@ func TestGetBlocksAtHeight(t *testing.T) {
 }
 
 func TestGetBlockByHash(t *testing.T) {
-	daemon, _ := useRPCTestnet(t)
+	server, client, _ := useRPCTestnet(t)
+	mined := server.MineBlock()
 
-	block, err := daemon.GetBlockByHash(GetBlockByHashParams{Hash: `23827b240a9e6aeb0e7164a4e402838ffc383efdc92789d705921fccfed516b5`})
+	block, err := client.GetBlockByHash(daemon.GetBlockByHashParams{Hash: mined.Hash})
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -111,9 +130,9 @@ func TestGetBlockByHash(t *testing.T) {
 }
 
 func TestGetTopBlock(t *testing.T) {
-	daemon, _ := useRPCTestnet(t)
+	_, client, _ := useRPCTestnet(t)
 
-	topBlock, err := daemon.GetTopBlock(GetTopBlockParams{})
+	topBlock, err := client.GetTopBlock(daemon.GetTopBlockParams{})
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -121,9 +140,9 @@ func TestGetTopBlock(t *testing.T) {
 }
 
 func TestGetInfo(t *testing.T) {
-	daemon, _ := useRPCTestnet(t)
+	_, client, _ := useRPCTestnet(t)
 
-	info, err := daemon.GetInfo()
+	info, err := client.GetInfo()
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -131,9 +150,9 @@ func TestGetInfo(t *testing.T) {
 }
 
 func TestGetAsset(t *testing.T) {
-	daemon, _ := useRPCTestnet(t)
+	_, client, _ := useRPCTestnet(t)
 
-	asset, err := daemon.GetAsset(config.XELIS_ASSET)
+	asset, err := client.GetAsset(config.XELIS_ASSET)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -141,9 +160,9 @@ func TestGetAsset(t *testing.T) {
 }
 
 func TestGetAssets(t *testing.T) {
-	daemon, _ := useRPCTestnet(t)
+	_, client, _ := useRPCTestnet(t)
 
-	assets, err := daemon.GetAssets(GetAssetsParams{})
+	assets, err := client.GetAssets(daemon.GetAssetsParams{})
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -151,9 +170,9 @@ func TestGetAssets(t *testing.T) {
 }
 
 func TestCountAssets(t *testing.T) {
-	daemon, _ := useRPCTestnet(t)
+	_, client, _ := useRPCTestnet(t)
 
-	countAssets, err := daemon.CountAssets()
+	countAssets, err := client.CountAssets()
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -161,9 +180,9 @@ func TestCountAssets(t *testing.T) {
 }
 
 func TestCountAccounts(t *testing.T) {
-	daemon, _ := useRPCTestnet(t)
+	_, client, _ := useRPCTestnet(t)
 
-	countAccounts, err := daemon.CountAccounts()
+	countAccounts, err := client.CountAccounts()
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -171,9 +190,9 @@ func TestCountAccounts(t *testing.T) {
 }
 
 func TestCountTransactions(t *testing.T) {
-	daemon, _ := useRPCTestnet(t)
+	_, client, _ := useRPCTestnet(t)
 
-	countTransactions, err := daemon.CountTransactions()
+	countTransactions, err := client.CountTransactions()
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -182,9 +201,9 @@ func TestCountTransactions(t *testing.T) {
 }
 
 func TestP2PStatus(t *testing.T) {
-	daemon, _ := useRPCTestnet(t)
+	_, client, _ := useRPCTestnet(t)
 
-	status, err := daemon.P2PStatus()
+	status, err := client.P2PStatus()
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -192,9 +211,9 @@ func TestP2PStatus(t *testing.T) {
 }
 
 func TestGetPeers(t *testing.T) {
-	daemon, _ := useRPCTestnet(t)
+	_, client, _ := useRPCTestnet(t)
 
-	result, err := daemon.GetPeers()
+	result, err := client.GetPeers()
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -203,9 +222,9 @@ func TestGetPeers(t *testing.T) {
 }
 
 func TestGetMempool(t *testing.T) {
-	daemon, _ := useRPCTestnet(t)
+	_, client, _ := useRPCTestnet(t)
 
-	mempool, err := daemon.GetMempool()
+	mempool, err := client.GetMempool()
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -213,9 +232,9 @@ func TestGetMempool(t *testing.T) {
 }
 
 func TestGetTips(t *testing.T) {
-	daemon, _ := useRPCTestnet(t)
+	_, client, _ := useRPCTestnet(t)
 
-	tips, err := daemon.GetTips()
+	tips, err := client.GetTips()
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -223,9 +242,9 @@ func TestGetTips(t *testing.T) {
 }
 
 func TestGetDAGOrder(t *testing.T) {
-	daemon, _ := useRPCTestnet(t)
+	_, client, _ := useRPCTestnet(t)
 
-	dagOrder, err := daemon.GetDAGOrder(GetTopoheightRangeParams{})
+	dagOrder, err := client.GetDAGOrder(daemon.GetTopoheightRangeParams{})
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -233,9 +252,9 @@ func TestGetDAGOrder(t *testing.T) {
 }
 
 func TestGetAccounts(t *testing.T) {
-	daemon, _ := useRPCTestnet(t)
+	_, client, _ := useRPCTestnet(t)
 
-	accounts, err := daemon.GetAccounts(GetAccountsParams{Maximum: 5})
+	accounts, err := client.GetAccounts(daemon.GetAccountsParams{Maximum: 5})
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -243,9 +262,9 @@ func TestGetAccounts(t *testing.T) {
 }
 
 func TestGetDevFeeThresholds(t *testing.T) {
-	daemon, _ := useRPCTestnet(t)
+	_, client, _ := useRPCTestnet(t)
 
-	fees, err := daemon.GetDevFeeThresholds()
+	fees, err := client.GetDevFeeThresholds()
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -253,9 +272,9 @@ func TestGetDevFeeThresholds(t *testing.T) {
 }
 
 func TestGetSizeOnDisk(t *testing.T) {
-	daemon, _ := useRPCTestnet(t)
+	_, client, _ := useRPCTestnet(t)
 
-	size, err := daemon.GetSizeOnDisk()
+	size, err := client.GetSizeOnDisk()
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -263,9 +282,9 @@ func TestGetSizeOnDisk(t *testing.T) {
 }
 
 func TestGetDifficulty(t *testing.T) {
-	daemon, _ := useRPCTestnet(t)
+	_, client, _ := useRPCTestnet(t)
 
-	diff, err := daemon.GetDifficulty()
+	diff, err := client.GetDifficulty()
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -273,9 +292,9 @@ func TestGetDifficulty(t *testing.T) {
 }
 
 func TestValidateAddress(t *testing.T) {
-	daemon, _ := useRPCTestnet(t)
+	_, client, _ := useRPCTestnet(t)
 
-	validAddr, err := daemon.ValidateAddress(ValidateAddressParams{
+	validAddr, err := client.ValidateAddress(daemon.ValidateAddressParams{
 		Address:         TESTING_ADDR,
 		AllowIntegrated: false,
 	})
@@ -284,7 +303,7 @@ func TestValidateAddress(t *testing.T) {
 	}
 	t.Logf("%+v", validAddr)
 
-	bytePublicKey, err := daemon.ExtractKeyFromAddress(ExtractKeyFromAddressParams{
+	bytePublicKey, err := client.ExtractKeyFromAddress(daemon.ExtractKeyFromAddressParams{
 		Address: TESTING_ADDR,
 		AsHex:   false,
 	})
@@ -293,7 +312,7 @@ func TestValidateAddress(t *testing.T) {
 	}
 	t.Logf("%+v", bytePublicKey)
 
-	hexPublicKey, err := daemon.ExtractKeyFromAddress(ExtractKeyFromAddressParams{
+	hexPublicKey, err := client.ExtractKeyFromAddress(daemon.ExtractKeyFromAddressParams{
 		Address: TESTING_ADDR,
 		AsHex:   true,
 	})
@@ -304,8 +323,8 @@ func TestValidateAddress(t *testing.T) {
 }
 
 func TestRPCUnknownMethod(t *testing.T) {
-	daemon, ctx := useRPCTestnet(t)
-	res, err := daemon.Client.Call(ctx, "UnknownMethod", nil)
+	_, client, ctx := useRPCTestnet(t)
+	res, err := client.Client.Call(ctx, "UnknownMethod", nil)
 	if err == nil {
 		t.Fatal("Expected an error")
 	}
@@ -314,15 +333,15 @@ func TestRPCUnknownMethod(t *testing.T) {
 }
 
 func TestRPCNonceAndBalance(t *testing.T) {
-	daemon, _ := useRPCTestnet(t)
-	has, err := daemon.HasNonce(TESTING_ADDR)
+	_, client, _ := useRPCTestnet(t)
+	has, err := client.HasNonce(TESTING_ADDR)
 	if err != nil {
 		t.Fatal(err)
 	}
 
 	t.Log(has)
 
-	has, err = daemon.HasBalance(GetBalanceParams{
+	has, err = client.HasBalance(daemon.GetBalanceParams{
 		Address: TESTING_ADDR,
 		Asset:   config.XELIS_ASSET,
 	})
@@ -332,14 +351,14 @@ func TestRPCNonceAndBalance(t *testing.T) {
 
 	t.Log(has)
 
-	nonce, err := daemon.GetNonce(TESTING_ADDR)
+	nonce, err := client.GetNonce(TESTING_ADDR)
 	if err != nil {
 		t.Fatal(err)
 	}
 
 	t.Log(nonce)
 
-	versionedNonce, err := daemon.GetNonceAtTopoheight(GetNonceAtTopoheightParams{
+	versionedNonce, err := client.GetNonceAtTopoheight(daemon.GetNonceAtTopoheightParams{
 		Address:    TESTING_ADDR,
 		Topoheight: nonce.Topoheight,
 	})
@@ -349,7 +368,7 @@ func TestRPCNonceAndBalance(t *testing.T) {
 
 	t.Log(versionedNonce)
 
-	balance, err := daemon.GetBalance(GetBalanceParams{
+	balance, err := client.GetBalance(daemon.GetBalanceParams{
 		Address: TESTING_ADDR,
 		Asset:   config.XELIS_ASSET,
 	})
@@ -359,7 +378,7 @@ func TestRPCNonceAndBalance(t *testing.T) {
 
 	t.Log(balance)
 
-	stableBalance, err := daemon.GetStableBalance(GetBalanceParams{
+	stableBalance, err := client.GetStableBalance(daemon.GetBalanceParams{
 		Address: TESTING_ADDR,
 		Asset:   config.XELIS_ASSET,
 	})
@@ -369,7 +388,7 @@ func TestRPCNonceAndBalance(t *testing.T) {
 
 	t.Log(stableBalance)
 
-	versionedBalance, err := daemon.GetBalanceAtTopoheight(GetBalanceAtTopoheightParams{
+	versionedBalance, err := client.GetBalanceAtTopoheight(daemon.GetBalanceAtTopoheightParams{
 		Address:    TESTING_ADDR,
 		Asset:      config.XELIS_ASSET,
 		Topoheight: nonce.Topoheight, // the testing addr does not have a balance before 322
@@ -382,14 +401,14 @@ func TestRPCNonceAndBalance(t *testing.T) {
 }
 
 func TestRPCGetBlocksRange(t *testing.T) {
-	daemon, _ := useRPCTestnet(t)
+	_, client, _ := useRPCTestnet(t)
 
-	topoheight, err := daemon.GetTopoheight()
+	topoheight, err := client.GetTopoheight()
 	if err != nil {
 		t.Fatal(err)
 	}
 
-	blocks, err := daemon.GetBlocksRangeByTopoheight(GetTopoheightRangeParams{
+	blocks, err := client.GetBlocksRangeByTopoheight(daemon.GetTopoheightRangeParams{
 		StartTopoheight: topoheight - 10,
 		EndTopoheight:   topoheight,
 	})
@@ -399,12 +418,12 @@ func TestRPCGetBlocksRange(t *testing.T) {
 
 	t.Log(blocks)
 
-	height, err := daemon.GetHeight()
+	height, err := client.GetHeight()
 	if err != nil {
 		t.Fatal(err)
 	}
 
-	blocks, err = daemon.GetBlocksRangeByHeight(GetHeightRangeParams{
+	blocks, err = client.GetBlocksRangeByHeight(daemon.GetHeightRangeParams{
 		StartHeight: height - 10,
 		EndHeight:   height,
 	})
@@ -416,10 +435,10 @@ func TestRPCGetBlocksRange(t *testing.T) {
 }
 
 func TestRPCGetTransactions(t *testing.T) {
-	daemon, _ := useRPCTestnet(t)
-	txHash := "d9a6810d667c212e499ceb2acf60a8fbc0096da66b1e7a59fb3ae5d412ad58f2"
+	server, client, _ := useRPCTestnet(t)
+	txHash := server.AddTransaction(daemon.Transaction{}).Hash
 
-	txs, err := daemon.GetTransactions(GetTransactionsParams{
+	txs, err := client.GetTransactions(daemon.GetTransactionsParams{
 		TxHashes: []string{txHash},
 	})
 	if err != nil {
@@ -428,7 +447,7 @@ func TestRPCGetTransactions(t *testing.T) {
 
 	t.Log(txs)
 
-	tx, err := daemon.GetTransaction(txHash)
+	tx, err := client.GetTransaction(txHash)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -437,10 +456,11 @@ func TestRPCGetTransactions(t *testing.T) {
 }
 
 func TestRPCGetTransaction(t *testing.T) {
-	daemon, _ := useRPCTestnet(t)
-	txHash := "5f5e2ff1677860ee1f3e3c58ba188f427fbcb2f344dfb15dd0f7ca60b03f624c"
+	server, client, _ := useRPCTestnet(t)
+	txHash := server.AddTransaction(daemon.Transaction{}).Hash
+	server.MineBlock(txHash)
 
-	tx, err := daemon.GetTransaction(txHash)
+	tx, err := client.GetTransaction(txHash)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -449,11 +469,13 @@ func TestRPCGetTransaction(t *testing.T) {
 }
 
 func TestRPCExecutedInBlock(t *testing.T) {
-	// https://testnet-explorer.xelis.io/blocks/000000001849d07bbb4165c8ba1d1fc472a0629f56895efb8689e06ce62b3ca8
-	daemon, _ := useRPCTestnet(t)
-	executed, err := daemon.IsTxExecutedInBlock(IsTxExecutedInBlockParams{
-		TxHash:    "6e4bbd77b305fb68e2cc7576b4846d2db3617e3cbc2eb851cb2ae69b879e9d0f",
-		BlockHash: "000000001849d07bbb4165c8ba1d1fc472a0629f56895efb8689e06ce62b3ca8",
+	server, client, _ := useRPCTestnet(t)
+	tx := server.AddTransaction(daemon.Transaction{})
+	block := server.MineBlock(tx.Hash)
+
+	executed, err := client.IsTxExecutedInBlock(daemon.IsTxExecutedInBlockParams{
+		TxHash:    tx.Hash,
+		BlockHash: block.Hash,
 	})
 	if err != nil {
 		t.Fatal(err)
@@ -467,22 +489,22 @@ func TestRPCExecutedInBlock(t *testing.T) {
 }
 
 func TestRPCAccount(t *testing.T) {
-	daemon, _ := useRPCTestnet(t)
-	history, err := daemon.GetAccountHistory(TESTING_ADDR)
+	_, client, _ := useRPCTestnet(t)
+	history, err := client.GetAccountHistory(TESTING_ADDR)
 	if err != nil {
 		t.Fatal(err)
 	}
 
 	t.Log(history)
 
-	assets, err := daemon.GetAccountAssets(TESTING_ADDR)
+	assets, err := client.GetAccountAssets(TESTING_ADDR)
 	if err != nil {
 		t.Fatal(err)
 	}
 
 	t.Log(assets)
 
-	topoheight, err := daemon.GetAccountRegistrationTopoheight(TESTING_ADDR)
+	topoheight, err := client.GetAccountRegistrationTopoheight(TESTING_ADDR)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -491,18 +513,16 @@ func TestRPCAccount(t *testing.T) {
 }
 
 func TestRPCRegistration(t *testing.T) {
-	// using mainnet for this test
-	// we need to resync the blockchain to work on testnet
-	daemon, _ := useRPCMainnet(t)
+	_, client, _ := useRPCMainnet(t)
 
-	topoheight, err := daemon.GetAccountRegistrationTopoheight(MAINNET_ADDR)
+	topoheight, err := client.GetAccountRegistrationTopoheight(MAINNET_ADDR)
 	if err != nil {
 		t.Fatal(err)
 	}
 
 	t.Log(topoheight)
 
-	exists, err := daemon.IsAccountRegistered(IsAccountRegisteredParams{
+	exists, err := client.IsAccountRegistered(daemon.IsAccountRegisteredParams{
 		Address:        MAINNET_ADDR,
 		InStableHeight: true,
 	})
@@ -514,16 +534,16 @@ func TestRPCRegistration(t *testing.T) {
 }
 
 func TestGetMinerWork(t *testing.T) {
-	daemon, _ := useRPCTestnet(t)
+	_, client, _ := useRPCTestnet(t)
 
 	var addr = "xet:w64wu066sq7jq4v9f37a5gy8hgyvc2gvt237u2457mme2m2r7avqqtmufz3"
 
-	blockTemplate, err := daemon.GetBlockTemplate(addr)
+	blockTemplate, err := client.GetBlockTemplate(addr)
 	if err != nil {
 		t.Fatal(err)
 	}
 
-	result, err := daemon.GetMinerWork(GetMinerWorkParams{
+	result, err := client.GetMinerWork(daemon.GetMinerWorkParams{
 		Template: blockTemplate.Template,
 		Address:  &addr,
 	})
@@ -535,9 +555,9 @@ func TestGetMinerWork(t *testing.T) {
 }
 
 func TestSplitAddress(t *testing.T) {
-	daemon, _ := useRPCTestnet(t)
+	_, client, _ := useRPCTestnet(t)
 
-	result, err := daemon.SplitAddress(SplitAddressParams{Address: "xet:upqflhm65lmjtukavf4de93kphk4j990hw9x9hhrc8rwleduruhqzqqpqvcnydgd3plda"})
+	result, err := client.SplitAddress(daemon.SplitAddressParams{Address: "xet:upqflhm65lmjtukavf4de93kphk4j990hw9x9hhrc8rwleduruhqzqqpqvcnydgd3plda"})
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -1,5 +1,7 @@
 package daemon
 
+import "github.com/xelis-project/xelis-go-sdk/address"
+
 type GetTopoheightRangeParams struct {
 	StartTopoheight uint64 `json:"start_topoheight"`
 	EndTopoheight   uint64 `json:"end_topoheight"`
@@ -299,6 +301,12 @@ type AccountHistory struct {
 	DevFee         *MiningHistory   `json:"dev_fee"`
 }
 
+type BlockOrderedResult struct {
+	BlockHash  string `json:"block_hash"`
+	BlockType  string `json:"block_type"`
+	Topoheight uint64 `json:"topoheight"`
+}
+
 type TransactionExecutedResult struct {
 	BlockHash  string `json:"block_hash"`
 	Topoheight uint64 `json:"topoheight"`
@@ -366,8 +374,8 @@ type SplitAddressParams struct {
 }
 
 type SplitAddressResult struct {
-	Address        string      `json:"address"`
-	IntegratedData interface{} `json:"integrated_data"`
+	Address        string              `json:"address"`
+	IntegratedData address.DataElement `json:"integrated_data"`
 }
 
 const (
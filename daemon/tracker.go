@@ -0,0 +1,296 @@
+package daemon
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+type TxState string
+
+const (
+	TxInMempool TxState = "in_mempool"
+	TxExecuted  TxState = "executed"
+	TxStable    TxState = "stable"
+	// The block executing the transaction was orphaned and the transaction is back in the mempool.
+	TxOrphaned TxState = "orphaned"
+)
+
+type TxStatus struct {
+	TxHash string
+	State  TxState
+	// Block executing the transaction. For TxOrphaned, the block that was orphaned.
+	BlockHash  string
+	Topoheight uint64
+}
+
+type TxTrackerOptions struct {
+	// Interval between two checks of the tracked transactions. Defaults to 5 seconds.
+	PollInterval time.Duration
+	// Called with daemon errors. They are retried on the next check.
+	OnError func(err error)
+}
+
+// TxTracker follows submitted transactions until their block is below the stable topoheight.
+// It polls GetTransaction, IsTxExecutedInBlock and GetStableTopoheight,
+// and checks again right away on transaction_executed and new_block events.
+type TxTracker struct {
+	client  Client
+	ws      *WebSocket
+	options TxTrackerOptions
+
+	mutex     sync.Mutex
+	txs       map[string]*trackedTx
+	untracked []*trackedTx // closed by Run, the only goroutine sending statuses
+	wake      chan struct{}
+}
+
+type trackedTx struct {
+	statuses chan TxStatus
+	last     *TxStatus
+}
+
+// The WebSocket is optional. Without it the transactions are only polled.
+func NewTxTracker(client Client, ws *WebSocket, options TxTrackerOptions) *TxTracker {
+	if options.PollInterval <= 0 {
+		options.PollInterval = 5 * time.Second
+	}
+
+	return &TxTracker{
+		client:  client,
+		ws:      ws,
+		options: options,
+		txs:     make(map[string]*trackedTx),
+		wake:    make(chan struct{}, 1),
+	}
+}
+
+// Stream of status changes of the transaction.
+// The channel is closed once the transaction is stable, after Untrack or when Run returns.
+func (t *TxTracker) Track(hash string) <-chan TxStatus {
+	t.mutex.Lock()
+	tx, ok := t.txs[hash]
+	if !ok {
+		tx = &trackedTx{statuses: make(chan TxStatus, 16)}
+		t.txs[hash] = tx
+	}
+	t.mutex.Unlock()
+
+	t.notify()
+	return tx.statuses
+}
+
+func (t *TxTracker) Untrack(hash string) {
+	t.mutex.Lock()
+	tx, ok := t.txs[hash]
+	if ok {
+		t.untracked = append(t.untracked, tx)
+		delete(t.txs, hash)
+	}
+	t.mutex.Unlock()
+
+	t.notify()
+}
+
+func (t *TxTracker) closeUntracked() {
+	defer t.mutex.Unlock()
+	t.mutex.Lock()
+
+	for _, tx := range t.untracked {
+		close(tx.statuses)
+	}
+	t.untracked = nil
+}
+
+func (t *TxTracker) notify() {
+	select {
+	case t.wake <- struct{}{}:
+	default:
+	}
+}
+
+// Check the tracked transactions until the context is done.
+func (t *TxTracker) Run(ctx context.Context) error {
+	if t.ws != nil {
+		// Our own listeners, the other listeners of these events on the same connection are left untouched.
+		executed, err := t.ws.TransactionExecutedListener(func(result TransactionExecutedResult, err error) {
+			t.mutex.Lock()
+			_, ok := t.txs[result.TxHash]
+			t.mutex.Unlock()
+
+			if err == nil && ok {
+				t.notify()
+			}
+		})
+		if err != nil {
+			return err
+		}
+		defer executed.Close()
+
+		newBlock, err := t.ws.NewBlockListener(func(block Block, err error) {
+			t.notify()
+		})
+		if err != nil {
+			return err
+		}
+		defer newBlock.Close()
+	}
+
+	defer func() {
+		t.mutex.Lock()
+		for hash, tx := range t.txs {
+			t.untracked = append(t.untracked, tx)
+			delete(t.txs, hash)
+		}
+		t.mutex.Unlock()
+
+		t.closeUntracked()
+	}()
+
+	ticker := time.NewTicker(t.options.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		t.closeUntracked()
+		t.checkAll(ctx)
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-t.wake:
+		case <-ticker.C:
+		}
+	}
+}
+
+// Errors caused by the end of Run are not reported.
+func (t *TxTracker) onError(ctx context.Context, err error) {
+	if t.options.OnError != nil && err != nil && ctx.Err() == nil {
+		t.options.OnError(err)
+	}
+}
+
+func (t *TxTracker) checkAll(ctx context.Context) {
+	t.mutex.Lock()
+	hashes := make([]string, 0, len(t.txs))
+	for hash := range t.txs {
+		hashes = append(hashes, hash)
+	}
+	t.mutex.Unlock()
+
+	if len(hashes) == 0 {
+		return
+	}
+
+	stableTopoheight, err := t.client.GetStableTopoheightContext(ctx)
+	if err != nil {
+		t.onError(ctx, err)
+		return
+	}
+
+	for _, hash := range hashes {
+		status, ok := t.check(ctx, hash, stableTopoheight)
+		if ok {
+			t.update(ctx, status)
+		}
+	}
+}
+
+// Current status of the transaction. False if it can't be known right now.
+func (t *TxTracker) check(ctx context.Context, hash string, stableTopoheight uint64) (status TxStatus, ok bool) {
+	status.TxHash = hash
+
+	tx, err := t.client.GetTransactionContext(ctx, hash)
+	if err != nil {
+		// not propagated yet, other failures like storage errors are reported
+		if !errors.Is(err, ErrTransactionNotFound) {
+			t.onError(ctx, err)
+		}
+		return
+	}
+
+	if tx.InMempool {
+		status.State = TxInMempool
+		return status, true
+	}
+
+	if tx.ExecutedInBlock == nil {
+		return
+	}
+
+	executed, err := t.client.IsTxExecutedInBlockContext(ctx, IsTxExecutedInBlockParams{TxHash: hash, BlockHash: *tx.ExecutedInBlock})
+	if err != nil || !executed {
+		t.onError(ctx, err)
+		return
+	}
+
+	block, err := t.client.GetBlockByHashContext(ctx, GetBlockByHashParams{Hash: *tx.ExecutedInBlock})
+	if err != nil || block.Topoheight == nil {
+		t.onError(ctx, err)
+		return
+	}
+
+	status.State = TxExecuted
+	status.BlockHash = block.Hash
+	status.Topoheight = *block.Topoheight
+	if status.Topoheight <= stableTopoheight {
+		status.State = TxStable
+	}
+
+	return status, true
+}
+
+// Send the status if it changed. An executed transaction that is in the mempool again
+// or executed in another block is reported as orphaned first.
+func (t *TxTracker) update(ctx context.Context, status TxStatus) {
+	t.mutex.Lock()
+	tx, ok := t.txs[status.TxHash]
+	t.mutex.Unlock()
+
+	if !ok {
+		return
+	}
+
+	var statuses []TxStatus
+	last := tx.last
+	if last != nil && last.State == TxExecuted && status.BlockHash != last.BlockHash {
+		orphaned := *last
+		orphaned.State = TxOrphaned
+		statuses = append(statuses, orphaned)
+
+		// already reported as orphaned
+		if status.State == TxInMempool {
+			status = orphaned
+		}
+	}
+
+	// orphaned already means back in the mempool
+	unchanged := last != nil && (*last == status || (last.State == TxOrphaned && status.State == TxInMempool))
+	if !unchanged && (len(statuses) == 0 || statuses[0] != status) {
+		statuses = append(statuses, status)
+	}
+
+	for _, status := range statuses {
+		select {
+		case tx.statuses <- status:
+		case <-ctx.Done():
+			return
+		}
+	}
+
+	if !unchanged {
+		tx.last = &status
+	}
+
+	if status.State == TxStable {
+		defer t.mutex.Unlock()
+		t.mutex.Lock()
+
+		// otherwise already untracked and closed by closeUntracked
+		if t.txs[status.TxHash] == tx {
+			delete(t.txs, status.TxHash)
+			close(tx.statuses)
+		}
+	}
+}
@@ -1,6 +1,8 @@
 package daemon
 
 import (
+	"context"
+
 	"github.com/xelis-project/xelis-go-sdk/rpc"
 )
 
@@ -20,10 +22,23 @@ func NewWebSocket(endpoint string) (*WebSocket, error) {
 	}, nil
 }
 
+// Reconnects automatically and subscribes again to every event listened with the *Channel and *Func methods.
+func NewWebSocketWithReconnect(endpoint string, options rpc.ReconnectOptions) (*WebSocket, error) {
+	ws, err := rpc.NewWebSocketWithReconnect(endpoint, nil, options)
+	if err != nil {
+		return nil, err
+	}
+
+	return &WebSocket{
+		WS: ws,
+	}, nil
+}
+
 func (w *WebSocket) Close() error {
 	return w.WS.Close()
 }
 
+// Removes every listener of the event. The listeners returned by the *Listener methods can be closed on their own instead.
 func (w *WebSocket) CloseEvent(event string) error {
 	return w.WS.CloseEvent(event)
 }
@@ -50,7 +65,12 @@ func (w *WebSocket) NewBlockChannel() (chan Block, chan error, error) {
 }
 
 func (w *WebSocket) NewBlockFunc(onData func(Block, error)) error {
-	return w.WS.ListenEventFunc(NewBlock, func(res rpc.RPCResponse) {
+	_, err := w.NewBlockListener(onData)
+	return err
+}
+
+func (w *WebSocket) NewBlockListener(onData func(Block, error)) (*rpc.EventListener, error) {
+	return w.WS.AddEventListener(NewBlock, func(res rpc.RPCResponse) {
 		var result Block
 		err := rpc.JsonFormatResponse(res, nil, &result)
 		onData(result, err)
@@ -75,33 +95,43 @@ func (w *WebSocket) TransactionAddedInMempoolChannel() (chan Transaction, chan e
 }
 
 func (w *WebSocket) TransactionAddedInMempoolFunc(onData func(Transaction, error)) error {
-	return w.WS.ListenEventFunc(TransactionAddedInMempool, func(res rpc.RPCResponse) {
+	_, err := w.TransactionAddedInMempoolListener(onData)
+	return err
+}
+
+func (w *WebSocket) TransactionAddedInMempoolListener(onData func(Transaction, error)) (*rpc.EventListener, error) {
+	return w.WS.AddEventListener(TransactionAddedInMempool, func(res rpc.RPCResponse) {
 		var result Transaction
 		err := rpc.JsonFormatResponse(res, nil, &result)
 		onData(result, err)
 	})
 }
 
-func (w *WebSocket) BlockOrderedChannel() (chan Block, chan error, error) {
-	chanBlock := make(chan Block)
+func (w *WebSocket) BlockOrderedChannel() (chan BlockOrderedResult, chan error, error) {
+	chanBlockOrderedResult := make(chan BlockOrderedResult)
 	chanErr := make(chan error)
 
 	err := w.WS.ListenEventFunc(BlockOrdered, func(res rpc.RPCResponse) {
-		var result Block
+		var result BlockOrderedResult
 		err := rpc.JsonFormatResponse(res, nil, &result)
 		if err != nil {
 			chanErr <- err
 		} else {
-			chanBlock <- result
+			chanBlockOrderedResult <- result
 		}
 	})
 
-	return chanBlock, chanErr, err
+	return chanBlockOrderedResult, chanErr, err
 }
 
-func (w *WebSocket) BlockOrderedFunc(onData func(Block, error)) error {
-	return w.WS.ListenEventFunc(BlockOrdered, func(res rpc.RPCResponse) {
-		var result Block
+func (w *WebSocket) BlockOrderedFunc(onData func(BlockOrderedResult, error)) error {
+	_, err := w.BlockOrderedListener(onData)
+	return err
+}
+
+func (w *WebSocket) BlockOrderedListener(onData func(BlockOrderedResult, error)) (*rpc.EventListener, error) {
+	return w.WS.AddEventListener(BlockOrdered, func(res rpc.RPCResponse) {
+		var result BlockOrderedResult
 		err := rpc.JsonFormatResponse(res, nil, &result)
 		onData(result, err)
 	})
@@ -125,7 +155,12 @@ func (w *WebSocket) TransactionExecutedChannel() (chan TransactionExecutedResult
 }
 
 func (w *WebSocket) TransactionExecutedFunc(onData func(TransactionExecutedResult, error)) error {
-	return w.WS.ListenEventFunc(TransactionExecuted, func(res rpc.RPCResponse) {
+	_, err := w.TransactionExecutedListener(onData)
+	return err
+}
+
+func (w *WebSocket) TransactionExecutedListener(onData func(TransactionExecutedResult, error)) (*rpc.EventListener, error) {
+	return w.WS.AddEventListener(TransactionExecuted, func(res rpc.RPCResponse) {
 		var result TransactionExecutedResult
 		err := rpc.JsonFormatResponse(res, nil, &result)
 		onData(result, err)
@@ -150,7 +185,12 @@ func (w *WebSocket) PeerConnectedChannel() (chan Peer, chan error, error) {
 }
 
 func (w *WebSocket) PeerConnectedFunc(onData func(Peer, error)) error {
-	return w.WS.ListenEventFunc(PeerConnected, func(res rpc.RPCResponse) {
+	_, err := w.PeerConnectedListener(onData)
+	return err
+}
+
+func (w *WebSocket) PeerConnectedListener(onData func(Peer, error)) (*rpc.EventListener, error) {
+	return w.WS.AddEventListener(PeerConnected, func(res rpc.RPCResponse) {
 		var result Peer
 		err := rpc.JsonFormatResponse(res, nil, &result)
 		onData(result, err)
@@ -175,7 +215,12 @@ func (w *WebSocket) PeerDisconnectedChannel() (chan uint64, chan error, error) {
 }
 
 func (w *WebSocket) PeerDisconnectedFunc(onData func(uint64, error)) error {
-	return w.WS.ListenEventFunc(PeerDisconnected, func(res rpc.RPCResponse) {
+	_, err := w.PeerDisconnectedListener(onData)
+	return err
+}
+
+func (w *WebSocket) PeerDisconnectedListener(onData func(uint64, error)) (*rpc.EventListener, error) {
+	return w.WS.AddEventListener(PeerDisconnected, func(res rpc.RPCResponse) {
 		var peerId uint64
 		err := rpc.JsonFormatResponse(res, nil, &peerId)
 		onData(peerId, err)
@@ -200,7 +245,12 @@ func (w *WebSocket) PeerStateUpdatedChannel() (chan Peer, chan error, error) {
 }
 
 func (w *WebSocket) PeerStateUpdatedFunc(onData func(Peer, error)) error {
-	return w.WS.ListenEventFunc(PeerStateUpdated, func(res rpc.RPCResponse) {
+	_, err := w.PeerStateUpdatedListener(onData)
+	return err
+}
+
+func (w *WebSocket) PeerStateUpdatedListener(onData func(Peer, error)) (*rpc.EventListener, error) {
+	return w.WS.AddEventListener(PeerStateUpdated, func(res rpc.RPCResponse) {
 		var result Peer
 		err := rpc.JsonFormatResponse(res, nil, &result)
 		onData(result, err)
@@ -208,292 +258,484 @@ func (w *WebSocket) PeerStateUpdatedFunc(onData func(Peer, error)) error {
 }
 
 func (w *WebSocket) GetVersion() (version string, err error) {
-	res, err := w.WS.Call(w.Prefix+GetVersion, nil)
+	return w.GetVersionContext(context.Background())
+}
+
+func (w *WebSocket) GetVersionContext(ctx context.Context) (version string, err error) {
+	res, err := w.WS.CallContext(ctx, w.Prefix+GetVersion, nil)
 	err = rpc.JsonFormatResponse(res, err, &version)
 	return
 }
 
 func (w *WebSocket) GetInfo() (result GetInfoResult, err error) {
-	res, err := w.WS.Call(w.Prefix+GetInfo, nil)
+	return w.GetInfoContext(context.Background())
+}
+
+func (w *WebSocket) GetInfoContext(ctx context.Context) (result GetInfoResult, err error) {
+	res, err := w.WS.CallContext(ctx, w.Prefix+GetInfo, nil)
 	err = rpc.JsonFormatResponse(res, err, &result)
 	return
 }
 
 func (w *WebSocket) GetHeight() (height uint64, err error) {
-	res, err := w.WS.Call(w.Prefix+GetHeight, nil)
+	return w.GetHeightContext(context.Background())
+}
+
+func (w *WebSocket) GetHeightContext(ctx context.Context) (height uint64, err error) {
+	res, err := w.WS.CallContext(ctx, w.Prefix+GetHeight, nil)
 	err = rpc.JsonFormatResponse(res, err, &height)
 	return
 }
 
 func (w *WebSocket) GetTopoheight() (topoheight uint64, err error) {
-	res, err := w.WS.Call(w.Prefix+GetTopoHeight, nil)
+	return w.GetTopoheightContext(context.Background())
+}
+
+func (w *WebSocket) GetTopoheightContext(ctx context.Context) (topoheight uint64, err error) {
+	res, err := w.WS.CallContext(ctx, w.Prefix+GetTopoHeight, nil)
 	err = rpc.JsonFormatResponse(res, err, &topoheight)
 	return
 }
 
 func (w *WebSocket) GetStableHeight() (stableheight uint64, err error) {
-	res, err := w.WS.Call(w.Prefix+GetStableHeight, nil)
+	return w.GetStableHeightContext(context.Background())
+}
+
+func (w *WebSocket) GetStableHeightContext(ctx context.Context) (stableheight uint64, err error) {
+	res, err := w.WS.CallContext(ctx, w.Prefix+GetStableHeight, nil)
 	err = rpc.JsonFormatResponse(res, err, &stableheight)
 	return
 }
 
 func (w *WebSocket) GetStableTopoheight() (topoheight uint64, err error) {
-	res, err := w.WS.Call(w.Prefix+GetStableTopoheight, nil)
+	return w.GetStableTopoheightContext(context.Background())
+}
+
+func (w *WebSocket) GetStableTopoheightContext(ctx context.Context) (topoheight uint64, err error) {
+	res, err := w.WS.CallContext(ctx, w.Prefix+GetStableTopoheight, nil)
 	err = rpc.JsonFormatResponse(res, err, &topoheight)
 	return
 }
 
 func (w *WebSocket) GetStableBalance(params GetBalanceParams) (result GetStableBalanceResult, err error) {
-	res, err := w.WS.Call(w.Prefix+GetStableBalance, nil)
+	return w.GetStableBalanceContext(context.Background(), params)
+}
+
+func (w *WebSocket) GetStableBalanceContext(ctx context.Context, params GetBalanceParams) (result GetStableBalanceResult, err error) {
+	res, err := w.WS.CallContext(ctx, w.Prefix+GetStableBalance, params)
 	err = rpc.JsonFormatResponse(res, err, &result)
 	return
 }
 
 func (w *WebSocket) GetBlockTemplate(addr string) (result GetBlockTemplateResult, err error) {
+	return w.GetBlockTemplateContext(context.Background(), addr)
+}
+
+func (w *WebSocket) GetBlockTemplateContext(ctx context.Context, addr string) (result GetBlockTemplateResult, err error) {
 	params := map[string]string{"address": addr}
-	res, err := w.WS.Call(w.Prefix+GetBlockTemplate, params)
+	res, err := w.WS.CallContext(ctx, w.Prefix+GetBlockTemplate, params)
 	err = rpc.JsonFormatResponse(res, err, &result)
 	return
 }
 
 func (w *WebSocket) GetBlockAtTopoheight(params GetBlockAtTopoheightParams) (block Block, err error) {
-	res, err := w.WS.Call(w.Prefix+GetBlockAtTopoheight, params)
+	return w.GetBlockAtTopoheightContext(context.Background(), params)
+}
+
+func (w *WebSocket) GetBlockAtTopoheightContext(ctx context.Context, params GetBlockAtTopoheightParams) (block Block, err error) {
+	res, err := w.WS.CallContext(ctx, w.Prefix+GetBlockAtTopoheight, params)
 	err = rpc.JsonFormatResponse(res, err, &block)
 	return
 }
 
-func (w *WebSocket) GetBlocksAtHeight(params GetBlockAtTopoheightParams) (blocks []Block, err error) {
-	res, err := w.WS.Call(w.Prefix+GetBlocksAtHeight, params)
+func (w *WebSocket) GetBlocksAtHeight(params GetBlocksAtHeightParams) (blocks []Block, err error) {
+	return w.GetBlocksAtHeightContext(context.Background(), params)
+}
+
+func (w *WebSocket) GetBlocksAtHeightContext(ctx context.Context, params GetBlocksAtHeightParams) (blocks []Block, err error) {
+	res, err := w.WS.CallContext(ctx, w.Prefix+GetBlocksAtHeight, params)
 	err = rpc.JsonFormatResponse(res, err, &blocks)
 	return
 }
 
 func (w *WebSocket) GetBlockByHash(params GetBlockByHashParams) (block Block, err error) {
-	res, err := w.WS.Call(w.Prefix+GetBlockByHash, params)
+	return w.GetBlockByHashContext(context.Background(), params)
+}
+
+func (w *WebSocket) GetBlockByHashContext(ctx context.Context, params GetBlockByHashParams) (block Block, err error) {
+	res, err := w.WS.CallContext(ctx, w.Prefix+GetBlockByHash, params)
 	err = rpc.JsonFormatResponse(res, err, &block)
 	return
 }
 
 func (w *WebSocket) GetTopBlock(params GetTopBlockParams) (block Block, err error) {
-	res, err := w.WS.Call(w.Prefix+GetTopBlock, params)
+	return w.GetTopBlockContext(context.Background(), params)
+}
+
+func (w *WebSocket) GetTopBlockContext(ctx context.Context, params GetTopBlockParams) (block Block, err error) {
+	res, err := w.WS.CallContext(ctx, w.Prefix+GetTopBlock, params)
 	err = rpc.JsonFormatResponse(res, err, &block)
 	return
 }
 
 func (w *WebSocket) GetNonce(addr string) (nonce GetNonceResult, err error) {
+	return w.GetNonceContext(context.Background(), addr)
+}
+
+func (w *WebSocket) GetNonceContext(ctx context.Context, addr string) (nonce GetNonceResult, err error) {
 	params := map[string]string{"address": addr}
-	res, err := w.WS.Call(w.Prefix+GetNonce, params)
+	res, err := w.WS.CallContext(ctx, w.Prefix+GetNonce, params)
 	err = rpc.JsonFormatResponse(res, err, &nonce)
 	return
 }
 
 func (w *WebSocket) GetNonceAtTopoheight(params GetNonceAtTopoheightParams) (nonce VersionedNonce, err error) {
-	res, err := w.WS.Call(w.Prefix+GetNonceAtTopoheight, params)
+	return w.GetNonceAtTopoheightContext(context.Background(), params)
+}
+
+func (w *WebSocket) GetNonceAtTopoheightContext(ctx context.Context, params GetNonceAtTopoheightParams) (nonce VersionedNonce, err error) {
+	res, err := w.WS.CallContext(ctx, w.Prefix+GetNonceAtTopoheight, params)
 	err = rpc.JsonFormatResponse(res, err, &nonce)
 	return
 }
 
 func (w *WebSocket) HasNonce(addr string) (hasNonce bool, err error) {
+	return w.HasNonceContext(context.Background(), addr)
+}
+
+func (w *WebSocket) HasNonceContext(ctx context.Context, addr string) (hasNonce bool, err error) {
 	params := map[string]string{"address": addr}
-	res, err := w.WS.Call(w.Prefix+HasNonce, params)
-	err = rpc.JsonFormatResponse(res, err, &hasNonce)
+	res, err := w.WS.CallContext(ctx, w.Prefix+HasNonce, params)
+	var result map[string]bool
+	err = rpc.JsonFormatResponse(res, err, &result)
+	hasNonce = result["exist"]
 	return
 }
 
 func (w *WebSocket) GetBalance(params GetBalanceParams) (balance GetBalanceResult, err error) {
-	res, err := w.WS.Call(w.Prefix+GetBalance, params)
+	return w.GetBalanceContext(context.Background(), params)
+}
+
+func (w *WebSocket) GetBalanceContext(ctx context.Context, params GetBalanceParams) (balance GetBalanceResult, err error) {
+	res, err := w.WS.CallContext(ctx, w.Prefix+GetBalance, params)
 	err = rpc.JsonFormatResponse(res, err, &balance)
 	return
 }
 
 func (w *WebSocket) HasBalance(params GetBalanceParams) (hasBalance bool, err error) {
-	res, err := w.WS.Call(w.Prefix+HasBalance, params)
-	err = rpc.JsonFormatResponse(res, err, &hasBalance)
+	return w.HasBalanceContext(context.Background(), params)
+}
+
+func (w *WebSocket) HasBalanceContext(ctx context.Context, params GetBalanceParams) (hasBalance bool, err error) {
+	res, err := w.WS.CallContext(ctx, w.Prefix+HasBalance, params)
+	var result map[string]bool
+	err = rpc.JsonFormatResponse(res, err, &result)
+	hasBalance = result["exist"]
 	return
 }
 
 func (w *WebSocket) GetBalanceAtTopoheight(params GetBalanceAtTopoheightParams) (balance VersionedBalance, err error) {
-	res, err := w.WS.Call(w.Prefix+GetBalanceAtTopoheight, params)
+	return w.GetBalanceAtTopoheightContext(context.Background(), params)
+}
+
+func (w *WebSocket) GetBalanceAtTopoheightContext(ctx context.Context, params GetBalanceAtTopoheightParams) (balance VersionedBalance, err error) {
+	res, err := w.WS.CallContext(ctx, w.Prefix+GetBalanceAtTopoheight, params)
 	err = rpc.JsonFormatResponse(res, err, &balance)
 	return
 }
 
 func (w *WebSocket) GetAsset(assetId string) (asset Asset, err error) {
+	return w.GetAssetContext(context.Background(), assetId)
+}
+
+func (w *WebSocket) GetAssetContext(ctx context.Context, assetId string) (asset Asset, err error) {
 	params := map[string]string{"asset": assetId}
-	res, err := w.WS.Call(w.Prefix+GetAsset, params)
+	res, err := w.WS.CallContext(ctx, w.Prefix+GetAsset, params)
 	err = rpc.JsonFormatResponse(res, err, &asset)
 	return
 }
 
 func (w *WebSocket) GetAssets(params GetAssetsParams) (assets []AssetWithData, err error) {
-	res, err := w.WS.Call(w.Prefix+GetAssets, params)
+	return w.GetAssetsContext(context.Background(), params)
+}
+
+func (w *WebSocket) GetAssetsContext(ctx context.Context, params GetAssetsParams) (assets []AssetWithData, err error) {
+	res, err := w.WS.CallContext(ctx, w.Prefix+GetAssets, params)
 	err = rpc.JsonFormatResponse(res, err, &assets)
 	return
 }
 
 func (w *WebSocket) CountAssets() (count uint64, err error) {
-	res, err := w.WS.Call(w.Prefix+CountAssets, nil)
+	return w.CountAssetsContext(context.Background())
+}
+
+func (w *WebSocket) CountAssetsContext(ctx context.Context) (count uint64, err error) {
+	res, err := w.WS.CallContext(ctx, w.Prefix+CountAssets, nil)
 	err = rpc.JsonFormatResponse(res, err, &count)
 	return
 }
 
 func (w *WebSocket) CountTransactions() (count uint64, err error) {
-	res, err := w.WS.Call(w.Prefix+CountTransactions, nil)
+	return w.CountTransactionsContext(context.Background())
+}
+
+func (w *WebSocket) CountTransactionsContext(ctx context.Context) (count uint64, err error) {
+	res, err := w.WS.CallContext(ctx, w.Prefix+CountTransactions, nil)
 	err = rpc.JsonFormatResponse(res, err, &count)
 	return
 }
 
 func (w *WebSocket) CountAccounts() (count uint64, err error) {
-	res, err := w.WS.Call(w.Prefix+CountAccounts, nil)
+	return w.CountAccountsContext(context.Background())
+}
+
+func (w *WebSocket) CountAccountsContext(ctx context.Context) (count uint64, err error) {
+	res, err := w.WS.CallContext(ctx, w.Prefix+CountAccounts, nil)
 	err = rpc.JsonFormatResponse(res, err, &count)
 	return
 }
 
 func (w *WebSocket) GetTips() (tips []string, err error) {
-	res, err := w.WS.Call(w.Prefix+GetTips, nil)
+	return w.GetTipsContext(context.Background())
+}
+
+func (w *WebSocket) GetTipsContext(ctx context.Context) (tips []string, err error) {
+	res, err := w.WS.CallContext(ctx, w.Prefix+GetTips, nil)
 	err = rpc.JsonFormatResponse(res, err, &tips)
 	return
 }
 
 func (w *WebSocket) P2PStatus() (status P2PStatusResult, err error) {
-	res, err := w.WS.Call(w.Prefix+P2PStatus, nil)
+	return w.P2PStatusContext(context.Background())
+}
+
+func (w *WebSocket) P2PStatusContext(ctx context.Context) (status P2PStatusResult, err error) {
+	res, err := w.WS.CallContext(ctx, w.Prefix+P2PStatus, nil)
 	err = rpc.JsonFormatResponse(res, err, &status)
 	return
 }
 
 func (w *WebSocket) GetDAGOrder(params GetTopoheightRangeParams) (hashes []string, err error) {
-	res, err := w.WS.Call(w.Prefix+GetDAGOrder, params)
+	return w.GetDAGOrderContext(context.Background(), params)
+}
+
+func (w *WebSocket) GetDAGOrderContext(ctx context.Context, params GetTopoheightRangeParams) (hashes []string, err error) {
+	res, err := w.WS.CallContext(ctx, w.Prefix+GetDAGOrder, params)
 	err = rpc.JsonFormatResponse(res, err, &hashes)
 	return
 }
 
 func (w *WebSocket) SubmitBlock(params SubmitBlockParams) (result bool, err error) {
-	res, err := w.WS.Call(w.Prefix+SubmitBlock, params)
+	return w.SubmitBlockContext(context.Background(), params)
+}
+
+func (w *WebSocket) SubmitBlockContext(ctx context.Context, params SubmitBlockParams) (result bool, err error) {
+	res, err := w.WS.CallContext(ctx, w.Prefix+SubmitBlock, params)
 	err = rpc.JsonFormatResponse(res, err, &result)
 	return
 }
 
 func (w *WebSocket) SubmitTransaction(hexData string) (result bool, err error) {
+	return w.SubmitTransactionContext(context.Background(), hexData)
+}
+
+func (w *WebSocket) SubmitTransactionContext(ctx context.Context, hexData string) (result bool, err error) {
 	params := map[string]string{"data": hexData}
-	res, err := w.WS.Call(w.Prefix+SubmitTransaction, params)
-	err = rpc.JsonFormatResponse(res, err, &hexData)
+	res, err := w.WS.CallContext(ctx, w.Prefix+SubmitTransaction, params)
+	err = rpc.JsonFormatResponse(res, err, &result)
 	return
 }
 
 func (w *WebSocket) GetMempool() (txs []Transaction, err error) {
-	res, err := w.WS.Call(w.Prefix+GetMempool, nil)
+	return w.GetMempoolContext(context.Background())
+}
+
+func (w *WebSocket) GetMempoolContext(ctx context.Context) (txs []Transaction, err error) {
+	res, err := w.WS.CallContext(ctx, w.Prefix+GetMempool, nil)
 	err = rpc.JsonFormatResponse(res, err, &txs)
 	return
 }
 
 func (w *WebSocket) GetTransaction(hash string) (tx Transaction, err error) {
+	return w.GetTransactionContext(context.Background(), hash)
+}
+
+func (w *WebSocket) GetTransactionContext(ctx context.Context, hash string) (tx Transaction, err error) {
 	params := map[string]string{"hash": hash}
-	res, err := w.WS.Call(w.Prefix+GetTransaction, params)
+	res, err := w.WS.CallContext(ctx, w.Prefix+GetTransaction, params)
 	err = rpc.JsonFormatResponse(res, err, &tx)
 	return
 }
 
 func (w *WebSocket) GetTransactions(params GetTransactionsParams) (txs []Transaction, err error) {
-	res, err := w.WS.Call(w.Prefix+GetTransactions, params)
+	return w.GetTransactionsContext(context.Background(), params)
+}
+
+func (w *WebSocket) GetTransactionsContext(ctx context.Context, params GetTransactionsParams) (txs []Transaction, err error) {
+	res, err := w.WS.CallContext(ctx, w.Prefix+GetTransactions, params)
 	err = rpc.JsonFormatResponse(res, err, &txs)
 	return
 }
 
 func (w *WebSocket) GetBlocksRangeByTopoheight(params GetTopoheightRangeParams) (blocks []Block, err error) {
-	res, err := w.WS.Call(w.Prefix+GetBlocksRangeByTopoheight, params)
+	return w.GetBlocksRangeByTopoheightContext(context.Background(), params)
+}
+
+func (w *WebSocket) GetBlocksRangeByTopoheightContext(ctx context.Context, params GetTopoheightRangeParams) (blocks []Block, err error) {
+	res, err := w.WS.CallContext(ctx, w.Prefix+GetBlocksRangeByTopoheight, params)
 	err = rpc.JsonFormatResponse(res, err, &blocks)
 	return
 }
 
 func (w *WebSocket) GetBlocksRangeByHeight(params GetHeightRangeParams) (blocks []Block, err error) {
-	res, err := w.WS.Call(w.Prefix+GetBlocksRangeByHeight, params)
+	return w.GetBlocksRangeByHeightContext(context.Background(), params)
+}
+
+func (w *WebSocket) GetBlocksRangeByHeightContext(ctx context.Context, params GetHeightRangeParams) (blocks []Block, err error) {
+	res, err := w.WS.CallContext(ctx, w.Prefix+GetBlocksRangeByHeight, params)
 	err = rpc.JsonFormatResponse(res, err, &blocks)
 	return
 }
 
 func (w *WebSocket) GetAccounts(params GetAccountsParams) (addresses []string, err error) {
-	res, err := w.WS.Call(w.Prefix+GetAccounts, params)
+	return w.GetAccountsContext(context.Background(), params)
+}
+
+func (w *WebSocket) GetAccountsContext(ctx context.Context, params GetAccountsParams) (addresses []string, err error) {
+	res, err := w.WS.CallContext(ctx, w.Prefix+GetAccounts, params)
 	err = rpc.JsonFormatResponse(res, err, &addresses)
 	return
 }
 
 func (w *WebSocket) GetAccountHistory(addr string) (history []AccountHistory, err error) {
+	return w.GetAccountHistoryContext(context.Background(), addr)
+}
+
+func (w *WebSocket) GetAccountHistoryContext(ctx context.Context, addr string) (history []AccountHistory, err error) {
 	params := map[string]string{"address": addr}
-	res, err := w.WS.Call(w.Prefix+GetAccountHistory, params)
+	res, err := w.WS.CallContext(ctx, w.Prefix+GetAccountHistory, params)
 	err = rpc.JsonFormatResponse(res, err, &history)
 	return
 }
 
 func (w *WebSocket) GetAccountAssets(addr string) (assets []string, err error) {
+	return w.GetAccountAssetsContext(context.Background(), addr)
+}
+
+func (w *WebSocket) GetAccountAssetsContext(ctx context.Context, addr string) (assets []string, err error) {
 	params := map[string]string{"address": addr}
-	res, err := w.WS.Call(w.Prefix+GetAccountAssets, params)
+	res, err := w.WS.CallContext(ctx, w.Prefix+GetAccountAssets, params)
 	err = rpc.JsonFormatResponse(res, err, &assets)
 	return
 }
 
 func (w *WebSocket) GetPeers() (result GetPeersResult, err error) {
-	res, err := w.WS.Call(w.Prefix+GetPeers, nil)
+	return w.GetPeersContext(context.Background())
+}
+
+func (w *WebSocket) GetPeersContext(ctx context.Context) (result GetPeersResult, err error) {
+	res, err := w.WS.CallContext(ctx, w.Prefix+GetPeers, nil)
 	err = rpc.JsonFormatResponse(res, err, &result)
 	return
 }
 
 func (w *WebSocket) GetDevFeeThresholds() (fees []Fee, err error) {
-	res, err := w.WS.Call(w.Prefix+GetDevFeeThresholds, nil)
+	return w.GetDevFeeThresholdsContext(context.Background())
+}
+
+func (w *WebSocket) GetDevFeeThresholdsContext(ctx context.Context) (fees []Fee, err error) {
+	res, err := w.WS.CallContext(ctx, w.Prefix+GetDevFeeThresholds, nil)
 	err = rpc.JsonFormatResponse(res, err, &fees)
 	return
 }
 
 func (w *WebSocket) GetSizeOnDisk() (sizeOnDisk SizeOnDisk, err error) {
-	res, err := w.WS.Call(w.Prefix+GetSizeOnDisk, nil)
+	return w.GetSizeOnDiskContext(context.Background())
+}
+
+func (w *WebSocket) GetSizeOnDiskContext(ctx context.Context) (sizeOnDisk SizeOnDisk, err error) {
+	res, err := w.WS.CallContext(ctx, w.Prefix+GetSizeOnDisk, nil)
 	err = rpc.JsonFormatResponse(res, err, &sizeOnDisk)
 	return
 }
 
 func (w *WebSocket) IsTxExecutedInBlock(params IsTxExecutedInBlockParams) (executed bool, err error) {
-	res, err := w.WS.Call(w.Prefix+IsTxExecutedInBlock, params)
+	return w.IsTxExecutedInBlockContext(context.Background(), params)
+}
+
+func (w *WebSocket) IsTxExecutedInBlockContext(ctx context.Context, params IsTxExecutedInBlockParams) (executed bool, err error) {
+	res, err := w.WS.CallContext(ctx, w.Prefix+IsTxExecutedInBlock, params)
 	err = rpc.JsonFormatResponse(res, err, &executed)
 	return
 }
 
 func (w *WebSocket) GetAccountRegistrationTopoheight(addr string) (topoheight uint64, err error) {
+	return w.GetAccountRegistrationTopoheightContext(context.Background(), addr)
+}
+
+func (w *WebSocket) GetAccountRegistrationTopoheightContext(ctx context.Context, addr string) (topoheight uint64, err error) {
 	params := map[string]string{"address": addr}
-	res, err := w.WS.Call(w.Prefix+GetAccountRegistrationTopoheight, params)
+	res, err := w.WS.CallContext(ctx, w.Prefix+GetAccountRegistrationTopoheight, params)
 	err = rpc.JsonFormatResponse(res, err, &topoheight)
 	return
 }
 
 func (w *WebSocket) IsAccountRegistered(params IsAccountRegisteredParams) (exists bool, err error) {
-	res, err := w.WS.Call(w.Prefix+IsAccountRegistered, params)
+	return w.IsAccountRegisteredContext(context.Background(), params)
+}
+
+func (w *WebSocket) IsAccountRegisteredContext(ctx context.Context, params IsAccountRegisteredParams) (exists bool, err error) {
+	res, err := w.WS.CallContext(ctx, w.Prefix+IsAccountRegistered, params)
 	err = rpc.JsonFormatResponse(res, err, &exists)
 	return
 }
 
 func (w *WebSocket) GetDifficulty() (result GetDifficultyResult, err error) {
-	res, err := w.WS.Call(w.Prefix+GetDifficulty, nil)
+	return w.GetDifficultyContext(context.Background())
+}
+
+func (w *WebSocket) GetDifficultyContext(ctx context.Context) (result GetDifficultyResult, err error) {
+	res, err := w.WS.CallContext(ctx, w.Prefix+GetDifficulty, nil)
 	err = rpc.JsonFormatResponse(res, err, &result)
 	return
 }
 
 func (w *WebSocket) ValidateAddress(params ValidateAddressParams) (result ValidateAddressResult, err error) {
-	res, err := w.WS.Call(w.Prefix+ValidateAddress, params)
+	return w.ValidateAddressContext(context.Background(), params)
+}
+
+func (w *WebSocket) ValidateAddressContext(ctx context.Context, params ValidateAddressParams) (result ValidateAddressResult, err error) {
+	res, err := w.WS.CallContext(ctx, w.Prefix+ValidateAddress, params)
 	err = rpc.JsonFormatResponse(res, err, &result)
 	return
 }
 
 func (w *WebSocket) ExtractKeyFromAddress(params ExtractKeyFromAddressParams) (key interface{}, err error) {
-	res, err := w.WS.Call(w.Prefix+ExtractKeyFromAddress, params)
+	return w.ExtractKeyFromAddressContext(context.Background(), params)
+}
+
+func (w *WebSocket) ExtractKeyFromAddressContext(ctx context.Context, params ExtractKeyFromAddressParams) (key interface{}, err error) {
+	res, err := w.WS.CallContext(ctx, w.Prefix+ExtractKeyFromAddress, params)
 	err = rpc.JsonFormatResponse(res, err, &key)
 	return
 }
 
 func (w *WebSocket) GetMinerWork(params GetMinerWorkParams) (result GetMinerWorkResult, err error) {
-	res, err := w.WS.Call(w.Prefix+GetMinerWork, params)
+	return w.GetMinerWorkContext(context.Background(), params)
+}
+
+func (w *WebSocket) GetMinerWorkContext(ctx context.Context, params GetMinerWorkParams) (result GetMinerWorkResult, err error) {
+	res, err := w.WS.CallContext(ctx, w.Prefix+GetMinerWork, params)
 	err = rpc.JsonFormatResponse(res, err, &result)
 	return
 }
 
 func (w *WebSocket) SplitAddress(params SplitAddressParams) (result SplitAddressResult, err error) {
-	res, err := w.WS.Call(w.Prefix+SplitAddress, params)
+	return w.SplitAddressContext(context.Background(), params)
+}
+
+func (w *WebSocket) SplitAddressContext(ctx context.Context, params SplitAddressParams) (result SplitAddressResult, err error) {
+	res, err := w.WS.CallContext(ctx, w.Prefix+SplitAddress, params)
 	err = rpc.JsonFormatResponse(res, err, &result)
 	return
 }
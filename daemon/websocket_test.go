@@ -1,15 +1,17 @@
-package daemon
+package daemon_test
 
 import (
 	"sync"
 	"testing"
 
-	"github.com/xelis-project/xelis-go-sdk/config"
+	"github.com/xelis-project/xelis-go-sdk/daemon"
+	"github.com/xelis-project/xelis-go-sdk/daemon/daemontest"
 	"github.com/xelis-project/xelis-go-sdk/rpc"
 )
 
-func useWSTestnet(t *testing.T) (daemon *WebSocket) {
-	daemon, err := NewWebSocket(config.TESTNET_NODE_WS)
+func useWS(t *testing.T, network string) (server *daemontest.Server, client *daemon.WebSocket) {
+	server = useServer(t, network)
+	client, err := daemon.NewWebSocket(server.WSEndpoint())
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -17,76 +19,76 @@ func useWSTestnet(t *testing.T) (daemon *WebSocket) {
 	return
 }
 
-func useWSMainnet(t *testing.T) (daemon *WebSocket) {
-	daemon, err := NewWebSocket(config.MAINNET_NODE_WS)
-	if err != nil {
-		t.Fatal(err)
-	}
+func useWSTestnet(t *testing.T) (*daemontest.Server, *daemon.WebSocket) {
+	return useWS(t, "Testnet")
+}
 
-	return
+func useWSMainnet(t *testing.T) (*daemontest.Server, *daemon.WebSocket) {
+	return useWS(t, "Mainnet")
 }
 
 func TestWSGetVersion(t *testing.T) {
-	daemon := useWSTestnet(t)
+	_, client := useWSTestnet(t)
 
-	version, err := daemon.GetVersion()
+	version, err := client.GetVersion()
 	if err != nil {
 		t.Fatal(err)
 	}
 
 	t.Logf("%+v", version)
-	daemon.Close()
+	client.Close()
 }
 
 func TestWSGetInfo(t *testing.T) {
-	daemon := useWSTestnet(t)
+	_, client := useWSTestnet(t)
 
-	info, err := daemon.GetInfo()
+	info, err := client.GetInfo()
 	if err != nil {
 		t.Fatal(err)
 	}
 
 	t.Logf("%+v", info)
-	daemon.Close()
+	client.Close()
 }
 
 func TestWSGetDevFeeThresholds(t *testing.T) {
-	daemon := useWSTestnet(t)
+	_, client := useWSTestnet(t)
 
-	fees, err := daemon.GetDevFeeThresholds()
+	fees, err := client.GetDevFeeThresholds()
 	if err != nil {
 		t.Fatal(err)
 	}
 
 	t.Logf("%+v", fees)
-	daemon.Close()
+	client.Close()
 }
 
 func TestWSGetSizeOnDisk(t *testing.T) {
-	daemon := useWSTestnet(t)
+	_, client := useWSTestnet(t)
 
-	size, err := daemon.GetSizeOnDisk()
+	size, err := client.GetSizeOnDisk()
 	if err != nil {
 		t.Fatal(err)
 	}
 
 	t.Logf("%+v", size)
-	daemon.Close()
+	client.Close()
 }
 
 func TestWSCloseBeforeAndRetry(t *testing.T) {
+	server := useServer(t, "Testnet")
 	testClose := true
 retry:
-	daemon, err := NewWebSocket(config.TESTNET_NODE_WS)
+	client, err := daemon.NewWebSocket(server.WSEndpoint())
 	if err != nil {
 		t.Fatal(err)
 	}
 
 	if testClose {
-		daemon.Close()
+		client.Close()
 	}
 
-	_, err = daemon.GetInfo()
+	_, err = client.GetInfo()
 	if err != nil {
 		if !testClose {
 			t.Fatal(err)
@@ -98,10 +100,10 @@ retry:
 }
 
 func TestWSNewBlock(t *testing.T) {
-	daemon := useWSTestnet(t)
+	server, client := useWSTestnet(t)
 	var wg sync.WaitGroup
 	wg.Add(1)
-	err := daemon.NewBlockFunc(func(newBlock Block, err error) {
+	err := client.NewBlockFunc(func(newBlock daemon.Block, err error) {
 		t.Logf("%+v", newBlock)
 		wg.Done()
 	})
@@ -110,14 +112,15 @@ func TestWSNewBlock(t *testing.T) {
 		t.Fatal(err)
 	}
 
+	server.MineBlock()
 	wg.Wait()
-	daemon.Close()
+	client.Close()
 }
 
 func TestWSUnsubscribe(t *testing.T) {
-	daemon := useWSTestnet(t)
+	_, client := useWSTestnet(t)
 
-	err := daemon.NewBlockFunc(func(block Block, err error) {
+	err := client.NewBlockFunc(func(block daemon.Block, err error) {
 		t.Logf("%+v", block)
 	})
 
@@ -125,20 +128,20 @@ func TestWSUnsubscribe(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	err = daemon.CloseEvent(NewBlock)
+	err = client.CloseEvent(daemon.NewBlock)
 	if err != nil {
 		t.Fatal(err)
 	}
 
-	daemon.Close()
+	client.Close()
 }
 
 func TestWSCallAndMultiSubscribe(t *testing.T) {
-	daemon := useWSTestnet(t)
+	server, client := useWSTestnet(t)
 	var wg sync.WaitGroup
 
 	wg.Add(1)
-	err := daemon.WS.ListenEventFunc(NewBlock, func(res rpc.RPCResponse) {
+	err := client.WS.ListenEventFunc(daemon.NewBlock, func(res rpc.RPCResponse) {
 		t.Logf("%+v", res)
 		wg.Done()
 	})
@@ -147,7 +150,7 @@ func TestWSCallAndMultiSubscribe(t *testing.T) {
 	}
 
 	wg.Add(1)
-	err = daemon.WS.ListenEventFunc(NewBlock, func(res rpc.RPCResponse) {
+	err = client.WS.ListenEventFunc(daemon.NewBlock, func(res rpc.RPCResponse) {
 		t.Logf("%+v", res)
 		wg.Done()
 	})
@@ -155,64 +158,66 @@ func TestWSCallAndMultiSubscribe(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	info, err := daemon.GetInfo()
+	info, err := client.GetInfo()
 	if err != nil {
 		t.Fatal(err)
 	}
 
 	t.Logf("%+v", info)
 
+	server.MineBlock()
 	wg.Wait()
-	daemon.Close()
+	client.Close()
 }
 
 func TestWSPeers(t *testing.T) {
-	daemon := useWSTestnet(t)
+	server, client := useWSTestnet(t)
 	var wg sync.WaitGroup
 
 	wg.Add(1)
-	daemon.PeerConnectedFunc(func(p Peer, err error) {
+	client.PeerConnectedFunc(func(p daemon.Peer, err error) {
 		t.Logf("%+v", p)
 		wg.Done()
 	})
 
 	wg.Add(1)
-	daemon.PeerDisconnectedFunc(func(id uint64, err error) {
+	client.PeerDisconnectedFunc(func(id uint64, err error) {
 		t.Logf("%d", id)
 		wg.Done()
 	})
 
+	server.AddPeer(daemon.Peer{Id: 1})
+	server.RemovePeer(1)
 	wg.Wait()
-	daemon.Close()
+	client.Close()
 }
 
 func TestWSPeerUpdated(t *testing.T) {
-	daemon := useWSTestnet(t)
+	server, client := useWSTestnet(t)
 	var wg sync.WaitGroup
 
 	wg.Add(1)
-	daemon.PeerStateUpdatedFunc(func(p Peer, err error) {
+	client.PeerStateUpdatedFunc(func(p daemon.Peer, err error) {
 		t.Logf("%+v", p)
 		wg.Done()
 	})
 
+	server.Notify(daemon.PeerStateUpdated, daemon.Peer{Id: 1})
 	wg.Wait()
-	daemon.Close()
+	client.Close()
 }
 
 func TestWSRegistration(t *testing.T) {
-	// using mainnet for this test
-	// we need to resync the blockchain to work on testnet
-	daemon := useWSMainnet(t)
+	_, client := useWSMainnet(t)
 
-	topoheight, err := daemon.GetAccountRegistrationTopoheight(MAINNET_ADDR)
+	topoheight, err := client.GetAccountRegistrationTopoheight(MAINNET_ADDR)
 	if err != nil {
 		t.Fatal(err)
 	}
 
 	t.Log(topoheight)
 
-	exists, err := daemon.IsAccountRegistered(IsAccountRegisteredParams{
+	exists, err := client.IsAccountRegistered(daemon.IsAccountRegisteredParams{
 		Address:        MAINNET_ADDR,
 		InStableHeight: true,
 	})
@@ -225,13 +230,15 @@ func TestWSRegistration(t *testing.T) {
 }
 
 func TestNewBlockChannel(t *testing.T) {
-	daemon := useWSMainnet(t)
+	server, client := useWSMainnet(t)
 
-	newBlock, newBlockErr, err := daemon.NewBlockChannel()
+	newBlock, newBlockErr, err := client.NewBlockChannel()
 	if err != nil {
 		t.Fatal(err)
 	}
 
+	go server.MineBlock()
+
 	select {
 	case block := <-newBlock:
 		t.Logf("%+v", block)
@@ -239,5 +246,5 @@ func TestNewBlockChannel(t *testing.T) {
 		t.Log(err)
 	}
 
-	daemon.Close()
+	client.Close()
 }
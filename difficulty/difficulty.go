@@ -0,0 +1,157 @@
+// Package difficulty converts between block difficulties, 256-bit targets and hashrates.
+package difficulty
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/xelis-project/xelis-go-sdk/block"
+)
+
+var ErrZeroDifficulty = errors.New("difficulty cannot be zero")
+var ErrZeroTarget = errors.New("target cannot be zero")
+
+// Highest 256-bit value. A hash meets a difficulty if it is at or below MAX_TARGET / difficulty.
+var MAX_TARGET = new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 256), big.NewInt(1))
+
+// Difficulty is an unsigned big integer. The zero value is a zero difficulty.
+// It is encoded in JSON as a decimal string, like in the daemon responses.
+type Difficulty struct {
+	value *big.Int
+}
+
+func New(value uint64) Difficulty {
+	return Difficulty{value: new(big.Int).SetUint64(value)}
+}
+
+func NewFromBig(value *big.Int) (Difficulty, error) {
+	if value.Sign() < 0 {
+		return Difficulty{}, fmt.Errorf("negative difficulty %s", value)
+	}
+
+	return Difficulty{value: new(big.Int).Set(value)}, nil
+}
+
+// Parse a decimal difficulty, like Block.Difficulty or GetInfoResult.Difficulty.
+func NewFromString(value string) (Difficulty, error) {
+	result, ok := new(big.Int).SetString(value, 10)
+	if !ok {
+		return Difficulty{}, fmt.Errorf("invalid difficulty %q", value)
+	}
+
+	return NewFromBig(result)
+}
+
+// Difficulty of a target, the opposite of Target.
+func NewFromTarget(target *big.Int) (Difficulty, error) {
+	if target.Sign() <= 0 {
+		return Difficulty{}, ErrZeroTarget
+	}
+
+	return Difficulty{value: new(big.Int).Div(MAX_TARGET, target)}, nil
+}
+
+// Highest difficulty met by a proof-of-work hash, like the difficulty of a share.
+func NewFromHash(hash block.Hash) Difficulty {
+	value := new(big.Int).SetBytes(hash[:])
+	if value.Sign() == 0 {
+		return Difficulty{value: new(big.Int).Set(MAX_TARGET)}
+	}
+
+	return Difficulty{value: new(big.Int).Div(MAX_TARGET, value)}
+}
+
+func (d Difficulty) Big() *big.Int {
+	if d.value == nil {
+		return new(big.Int)
+	}
+
+	return new(big.Int).Set(d.value)
+}
+
+func (d Difficulty) IsZero() bool {
+	return d.value == nil || d.value.Sign() == 0
+}
+
+func (d Difficulty) Cmp(other Difficulty) int {
+	return d.Big().Cmp(other.Big())
+}
+
+func (d Difficulty) String() string {
+	return d.Big().String()
+}
+
+// Highest hash value meeting the difficulty.
+func (d Difficulty) Target() (*big.Int, error) {
+	if d.IsZero() {
+		return nil, ErrZeroDifficulty
+	}
+
+	return new(big.Int).Div(MAX_TARGET, d.value), nil
+}
+
+// Check if a proof-of-work hash, read as a big-endian integer, is at or below the difficulty target.
+func (d Difficulty) Check(hash block.Hash) (bool, error) {
+	target, err := d.Target()
+	if err != nil {
+		return false, err
+	}
+
+	return new(big.Int).SetBytes(hash[:]).Cmp(target) <= 0, nil
+}
+
+// Hashrate in hashes per second needed to find a block every block time target,
+// in milliseconds like GetInfoResult.BlockTimeTarget.
+func (d Difficulty) Hashrate(blockTimeTarget uint64) float64 {
+	if blockTimeTarget == 0 {
+		return 0
+	}
+
+	value, _ := new(big.Float).SetInt(d.Big()).Float64()
+	return value * 1000 / float64(blockTimeTarget)
+}
+
+// Expected time to find a block at a hashrate in hashes per second.
+func (d Difficulty) TimeToBlock(hashrate float64) time.Duration {
+	if hashrate <= 0 {
+		return time.Duration(1<<63 - 1)
+	}
+
+	value, _ := new(big.Float).SetInt(d.Big()).Float64()
+	seconds := value / hashrate
+	if seconds >= float64(1<<63-1)/float64(time.Second) {
+		return time.Duration(1<<63 - 1)
+	}
+
+	return time.Duration(seconds * float64(time.Second))
+}
+
+func (d Difficulty) MarshalJSON() ([]byte, error) {
+	return json.Marshal(d.String())
+}
+
+// Accepts a decimal string or a JSON number.
+func (d *Difficulty) UnmarshalJSON(data []byte) error {
+	var value string
+	err := json.Unmarshal(data, &value)
+	if err != nil {
+		var number json.Number
+		err = json.Unmarshal(data, &number)
+		if err != nil {
+			return err
+		}
+
+		value = number.String()
+	}
+
+	result, err := NewFromString(value)
+	if err != nil {
+		return err
+	}
+
+	*d = result
+	return nil
+}
@@ -0,0 +1,93 @@
+package difficulty
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/xelis-project/xelis-go-sdk/block"
+)
+
+func TestTarget(t *testing.T) {
+	d := New(1000)
+	target, err := d.Target()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	back, err := NewFromTarget(target)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if back.Cmp(d) != 0 {
+		t.Errorf("Expected difficulty %s, got %s", d, back)
+	}
+
+	var hash block.Hash
+	copy(hash[:], target.FillBytes(make([]byte, 32)))
+	ok, err := d.Check(hash)
+	if err != nil || !ok {
+		t.Errorf("Expected the target hash to meet the difficulty")
+	}
+
+	if NewFromHash(hash).Cmp(d) != 0 {
+		t.Errorf("Expected hash difficulty %s, got %s", d, NewFromHash(hash))
+	}
+
+	copy(hash[:], new(big.Int).Add(target, big.NewInt(1)).FillBytes(make([]byte, 32)))
+	ok, err = d.Check(hash)
+	if err != nil || ok {
+		t.Errorf("Expected a hash above the target to fail")
+	}
+
+	_, err = Difficulty{}.Target()
+	if err != ErrZeroDifficulty {
+		t.Errorf("Expected zero difficulty error, got %v", err)
+	}
+}
+
+func TestHashrate(t *testing.T) {
+	d := New(150000)
+	hashrate := d.Hashrate(15000)
+	if hashrate != 10000 {
+		t.Errorf("Expected hashrate 10000, got %f", hashrate)
+	}
+
+	if d.TimeToBlock(hashrate) != 15*time.Second {
+		t.Errorf("Expected 15s to block, got %s", d.TimeToBlock(hashrate))
+	}
+}
+
+func TestJSON(t *testing.T) {
+	var result struct {
+		Difficulty Difficulty `json:"difficulty"`
+		Number     Difficulty `json:"number"`
+	}
+
+	err := json.Unmarshal([]byte(`{"difficulty":"340282366920938463463374607431768211456","number":42}`), &result)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if result.Difficulty.String() != "340282366920938463463374607431768211456" || result.Number.Cmp(New(42)) != 0 {
+		t.Errorf("Unexpected difficulties %s %s", result.Difficulty, result.Number)
+	}
+
+	data, err := json.Marshal(result)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(data) != `{"difficulty":"340282366920938463463374607431768211456","number":"42"}` {
+		t.Errorf("Unexpected JSON %s", data)
+	}
+
+	for _, invalid := range []string{`"-1"`, `"abc"`, `1.5`, `true`} {
+		var d Difficulty
+		if json.Unmarshal([]byte(invalid), &d) == nil {
+			t.Errorf("Expected error for %s", invalid)
+		}
+	}
+}
@@ -0,0 +1,238 @@
+package getwork
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/xelis-project/xelis-go-sdk/block"
+	"github.com/xelis-project/xelis-go-sdk/rpc"
+)
+
+var ErrNotConnected = errors.New("getwork client is not connected")
+
+type EventType int
+
+const (
+	// Connection established, also after a reconnect.
+	ConnectedEvent EventType = iota
+	// Connection lost, Err is the reason. The client reconnects after a backoff.
+	DisconnectedEvent
+	// New job received, also set as the current job.
+	JobEvent
+	AcceptedEvent
+	// Submitted block rejected, Reason is the message of the daemon.
+	RejectedEvent
+	// Message not known by this version, or malformed with Err set. Message holds it with its raw value.
+	UnknownEvent
+)
+
+type Event struct {
+	Type    EventType
+	Job     BlockTemplate
+	Reason  string
+	Message Message
+	Err     error
+}
+
+type ClientOptions struct {
+	// Delay before the first reconnect attempt. It doubles after every failed attempt.
+	MinBackoff time.Duration
+	// Maximum delay between two reconnect attempts.
+	MaxBackoff time.Duration
+	// Size of the event stream buffer. Defaults to 64.
+	// When it is full, the connection is not read until events are received.
+	EventBuffer int
+}
+
+// Client is a getwork connection driven by a context. It reconnects with backoff until the context is done,
+// keeps the current job and delivers every message as an Event on a single stream.
+//
+//	client := getwork.NewClient(endpoint, minerAddress, worker, getwork.ClientOptions{})
+//	go client.Run(ctx)
+//	for event := range client.Events() {
+//		...
+//	}
+type Client struct {
+	endpoint string
+	options  ClientOptions
+	events   chan Event
+
+	mutex      sync.Mutex
+	conn       *websocket.Conn
+	job        *BlockTemplate
+	writeMutex sync.Mutex
+}
+
+func NewClient(endpoint, minerAddress, worker string, options ClientOptions) *Client {
+	if options.MinBackoff <= 0 {
+		options.MinBackoff = rpc.DefaultReconnectOptions.MinBackoff
+	}
+
+	if options.MaxBackoff <= 0 {
+		options.MaxBackoff = rpc.DefaultReconnectOptions.MaxBackoff
+	}
+
+	if options.MaxBackoff < options.MinBackoff {
+		options.MaxBackoff = options.MinBackoff
+	}
+
+	if options.EventBuffer <= 0 {
+		options.EventBuffer = 64
+	}
+
+	return &Client{
+		endpoint: fmt.Sprintf("%s/%s/%s", endpoint, minerAddress, worker),
+		options:  options,
+		events:   make(chan Event, options.EventBuffer),
+	}
+}
+
+// Stream of events. It is closed when Run returns.
+func (c *Client) Events() <-chan Event {
+	return c.events
+}
+
+// Current job. False if no job was received since the last connection.
+func (c *Client) Job() (BlockTemplate, bool) {
+	defer c.mutex.Unlock()
+	c.mutex.Lock()
+
+	if c.job == nil {
+		return BlockTemplate{}, false
+	}
+
+	return *c.job, true
+}
+
+func (c *Client) SubmitBlock(hexData string) error {
+	c.mutex.Lock()
+	conn := c.conn
+	c.mutex.Unlock()
+
+	if conn == nil {
+		return ErrNotConnected
+	}
+
+	defer c.writeMutex.Unlock()
+	c.writeMutex.Lock()
+	return conn.WriteJSON(SubmitParams{BlockTemplate: hexData})
+}
+
+func (c *Client) SubmitMinerWork(work block.MinerWork) error {
+	return c.SubmitBlock(work.EncodeHex())
+}
+
+// Connect and read messages until the context is done. It must be called only once.
+func (c *Client) Run(ctx context.Context) error {
+	defer close(c.events)
+
+	backoff := c.options.MinBackoff
+	for {
+		connected, err := c.connect(ctx)
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		if connected {
+			backoff = c.options.MinBackoff
+		}
+
+		if !c.emit(ctx, Event{Type: DisconnectedEvent, Err: err}) {
+			return ctx.Err()
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(backoff):
+		}
+
+		backoff *= 2
+		if backoff > c.options.MaxBackoff {
+			backoff = c.options.MaxBackoff
+		}
+	}
+}
+
+func (c *Client) emit(ctx context.Context, event Event) bool {
+	select {
+	case c.events <- event:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// Dial and read until the connection fails. The first value is false if the dial failed.
+func (c *Client) connect(ctx context.Context) (bool, error) {
+	conn, _, err := websocket.DefaultDialer.DialContext(ctx, c.endpoint, nil)
+	if err != nil {
+		return false, err
+	}
+
+	c.mutex.Lock()
+	c.conn = conn
+	c.mutex.Unlock()
+
+	done := make(chan struct{})
+	defer func() {
+		close(done)
+		conn.Close()
+
+		c.mutex.Lock()
+		c.conn = nil
+		c.job = nil
+		c.mutex.Unlock()
+	}()
+
+	go func() {
+		select {
+		case <-ctx.Done():
+			conn.Close()
+		case <-done:
+		}
+	}()
+
+	if !c.emit(ctx, Event{Type: ConnectedEvent}) {
+		return true, ctx.Err()
+	}
+
+	for {
+		_, msg, err := conn.ReadMessage()
+		if err != nil {
+			return true, err
+		}
+
+		event := newEvent(msg)
+		if event.Type == JobEvent {
+			job := event.Job
+			c.mutex.Lock()
+			c.job = &job
+			c.mutex.Unlock()
+		}
+
+		if !c.emit(ctx, event) {
+			return true, ctx.Err()
+		}
+	}
+}
+
+func newEvent(data []byte) Event {
+	msg, err := DecodeMessage(data)
+	if err != nil || !msg.IsKnown() {
+		return Event{Type: UnknownEvent, Message: msg, Err: err}
+	}
+
+	switch msg.Type {
+	case NewJob:
+		return Event{Type: JobEvent, Job: msg.Job.BlockTemplate(), Message: msg}
+	case BlockRejected:
+		return Event{Type: RejectedEvent, Reason: msg.Reason, Message: msg}
+	default:
+		return Event{Type: AcceptedEvent, Message: msg}
+	}
+}
@@ -0,0 +1,104 @@
+package getwork
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func nextEvent(t *testing.T, events <-chan Event, eventType EventType) Event {
+	for {
+		select {
+		case event, ok := <-events:
+			if !ok {
+				t.Fatal("event stream closed")
+			}
+
+			if event.Type == eventType {
+				return event
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("event %d was not received", eventType)
+		}
+	}
+}
+
+func TestClientReconnect(t *testing.T) {
+	server, endpoint := useServer(t)
+	client := NewClient(endpoint, TESTNET_WALLET, "rig-1", ClientOptions{MinBackoff: 10 * time.Millisecond})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- client.Run(ctx) }()
+
+	events := client.Events()
+	nextEvent(t, events, ConnectedEvent)
+	event := nextEvent(t, events, JobEvent)
+
+	job, ok := client.Job()
+	if !ok || job != event.Job {
+		t.Errorf("Expected current job %+v, got %+v", event.Job, job)
+	}
+
+	work, err := job.MinerWork()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = client.SubmitMinerWork(work)
+	if err != nil {
+		t.Fatal(err)
+	}
+	nextEvent(t, events, AcceptedEvent)
+
+	err = client.SubmitBlock("00")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	event = nextEvent(t, events, RejectedEvent)
+	if event.Reason != "invalid miner work" {
+		t.Errorf("Unexpected reason %s", event.Reason)
+	}
+
+	// drop the connection, the client must reconnect and get a new job
+	server.Close()
+	nextEvent(t, events, DisconnectedEvent)
+	nextEvent(t, events, ConnectedEvent)
+	event = nextEvent(t, events, JobEvent)
+	if event.Job.Height <= job.Height {
+		t.Errorf("Expected a new job, got %+v", event.Job)
+	}
+
+	cancel()
+	select {
+	case err := <-done:
+		if err != context.Canceled {
+			t.Errorf("Expected context canceled, got %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Run did not return")
+	}
+
+	for range events {
+	}
+
+	err = client.SubmitBlock(job.Template)
+	if err != ErrNotConnected {
+		t.Errorf("Expected not connected error, got %v", err)
+	}
+}
+
+func TestGetworkClose(t *testing.T) {
+	_, endpoint := useServer(t)
+	getwork, err := NewGetwork(endpoint, TESTNET_WALLET, "rig-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	getwork.Close()
+	getwork.Close()
+
+	for range getwork.Job {
+	}
+}
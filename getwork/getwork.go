@@ -1,11 +1,12 @@
 package getwork
 
 import (
-	"encoding/json"
 	"fmt"
 	"net/url"
+	"sync"
 
 	"github.com/gorilla/websocket"
+	"github.com/xelis-project/xelis-go-sdk/block"
 )
 
 type Getwork struct {
@@ -15,8 +16,12 @@ type Getwork struct {
 	AcceptedBlock chan bool
 	RejectedBlock chan string
 	Err           chan error
+
+	closeOnce sync.Once
+	closed    chan struct{}
 }
 
+// Use NewClient for a connection that reconnects and doesn't block on unread channels.
 func NewGetwork(endpoint, minerAddress, worker string) (*Getwork, error) {
 	socketUrl, err := url.Parse(fmt.Sprintf("%s/%s/%s", endpoint, minerAddress, worker))
 	if err != nil {
@@ -34,15 +39,26 @@ func NewGetwork(endpoint, minerAddress, worker string) (*Getwork, error) {
 		AcceptedBlock: make(chan bool),
 		RejectedBlock: make(chan string),
 		Err:           make(chan error),
+		closed:        make(chan struct{}),
 	}
 
+	// The only sender, so it closes the channels.
 	go func() {
-		defer getwork.Close()
+		defer func() {
+			getwork.Close()
+			close(getwork.Job)
+			close(getwork.AcceptedBlock)
+			close(getwork.RejectedBlock)
+			close(getwork.Err)
+		}()
 
 		for {
 			_, msg, err := conn.ReadMessage()
 			if err != nil {
-				getwork.Err <- err
+				select {
+				case getwork.Err <- err:
+				case <-getwork.closed:
+				}
 				return
 			}
 
@@ -53,64 +69,48 @@ func NewGetwork(endpoint, minerAddress, worker string) (*Getwork, error) {
 	return getwork, nil
 }
 
+// Close the connection. The channels are closed once the reader stops. It is safe to call more than once.
 func (w *Getwork) Close() {
-	close(w.Job)
-	close(w.AcceptedBlock)
-	close(w.RejectedBlock)
-	close(w.Err)
-	w.conn.Close()
+	w.closeOnce.Do(func() {
+		close(w.closed)
+		w.conn.Close()
+	})
 }
 
-func (w *Getwork) handleMessage(msg []byte) {
-	var res interface{}
-	err := json.Unmarshal(msg, &res)
+// Unknown messages are dropped, use NewClient to receive them.
+func (w *Getwork) handleMessage(data []byte) {
+	msg, err := DecodeMessage(data)
 	if err != nil {
-		w.Err <- err
+		select {
+		case w.Err <- err:
+		case <-w.closed:
+		}
 		return
 	}
 
-	jsonMap, ok := res.(map[string]interface{})
-	if ok {
-		blockTemplate, ok := jsonMap[NewJob].(map[string]interface{})
-		if ok {
-			bt := BlockTemplate{}
-
-			if blockTemplate["difficulty"] != nil {
-				bt.Difficulty = blockTemplate["difficulty"].(string)
-			}
-			if blockTemplate["height"] != nil {
-				bt.Height = uint64(blockTemplate["height"].(float64))
-			}
-			if blockTemplate["miner_work"] != nil {
-				bt.Template = blockTemplate["miner_work"].(string)
-			} else if blockTemplate["template"] != nil {
-				bt.Template = blockTemplate["template"].(string)
-			}
-			if blockTemplate["algorithm"] != nil {
-				bt.Algorithm = blockTemplate["algorithm"].(string)
-			}
-
-			w.Job <- bt
-			return
+	switch msg.Type {
+	case NewJob:
+		select {
+		case w.Job <- msg.Job.BlockTemplate():
+		case <-w.closed:
 		}
-
-		rejected, ok := jsonMap[BlockRejected].(string)
-		if ok {
-			w.RejectedBlock <- rejected
-			return
+	case BlockRejected:
+		select {
+		case w.RejectedBlock <- msg.Reason:
+		case <-w.closed:
 		}
-	}
-
-	value, ok := res.(string)
-	if ok {
-		if value == BlockAccepted {
-			w.AcceptedBlock <- true
-			return
+	case BlockAccepted:
+		select {
+		case w.AcceptedBlock <- true:
+		case <-w.closed:
 		}
 	}
 }
 
+func (w *Getwork) SubmitMinerWork(work block.MinerWork) error {
+	return w.SubmitBlock(work.EncodeHex())
+}
+
 func (w *Getwork) SubmitBlock(hexData string) (err error) {
-	data := map[string]interface{}{"block_template": hexData}
-	return w.conn.WriteJSON(data)
+	return w.conn.WriteJSON(SubmitParams{BlockTemplate: hexData})
 }
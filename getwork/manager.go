@@ -0,0 +1,287 @@
+package getwork
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/xelis-project/xelis-go-sdk/block"
+	"github.com/xelis-project/xelis-go-sdk/difficulty"
+)
+
+type WorkerConfig struct {
+	// Unique name, also sent to the daemon as the worker name.
+	Name         string
+	Endpoint     string
+	MinerAddress string
+}
+
+// Job received by one of the workers of a Manager.
+type WorkerJob struct {
+	Worker string
+	Job    BlockTemplate
+}
+
+type WorkerStats struct {
+	Name      string
+	Endpoint  string
+	Connected bool
+	Submitted uint64
+	Accepted  uint64
+	Rejected  uint64
+	// Number of rejected shares by reason.
+	RejectReasons map[string]uint64
+	// Sum of the job difficulty of every accepted share.
+	AcceptedDifficulty *big.Int
+	// Accepted difficulty per second since the manager started, in hashes per second.
+	Hashrate  float64
+	LastShare time.Time
+}
+
+// Number of jobs per worker whose shares can still be submitted.
+const RECENT_JOBS = 16
+
+type recentJob struct {
+	workHash   block.Hash
+	difficulty difficulty.Difficulty
+}
+
+type worker struct {
+	config WorkerConfig
+	client *Client
+	stats  WorkerStats
+	// difficulty of the submitted shares waiting for a response, in order
+	pending []difficulty.Difficulty
+	// last jobs received, oldest first
+	recent []recentJob
+	// latest job not read from Jobs yet
+	queued *BlockTemplate
+}
+
+// Manager runs a getwork client for every worker and fans their jobs out on a single stream.
+// It counts the shares of each worker from the block_accepted and block_rejected responses,
+// which the daemon sends in the order the shares were submitted.
+type Manager struct {
+	workers map[string]*worker
+	jobs    chan WorkerJob
+	mutex   sync.Mutex
+	started time.Time
+	// workers with a queued job, in the order the jobs were received
+	queue []*worker
+	wake  chan struct{}
+}
+
+func NewManager(workers []WorkerConfig, options ClientOptions) (*Manager, error) {
+	m := &Manager{
+		workers: make(map[string]*worker),
+		jobs:    make(chan WorkerJob),
+		wake:    make(chan struct{}, 1),
+	}
+
+	for _, config := range workers {
+		if _, ok := m.workers[config.Name]; ok || config.Name == "" {
+			return nil, fmt.Errorf("invalid or duplicate worker name %q", config.Name)
+		}
+
+		m.workers[config.Name] = &worker{
+			config: config,
+			client: NewClient(config.Endpoint, config.MinerAddress, config.Name, options),
+			stats: WorkerStats{
+				Name:               config.Name,
+				Endpoint:           config.Endpoint,
+				RejectReasons:      make(map[string]uint64),
+				AcceptedDifficulty: new(big.Int),
+			},
+		}
+	}
+
+	return m, nil
+}
+
+// Jobs of every worker. It is closed when Run returns.
+// A job waiting to be read is replaced by the next job of the same worker, so a slow reader only gets fresh jobs.
+func (m *Manager) Jobs() <-chan WorkerJob {
+	return m.jobs
+}
+
+// Run every worker until the context is done. It must be called only once.
+func (m *Manager) Run(ctx context.Context) error {
+	m.mutex.Lock()
+	m.started = time.Now()
+	m.mutex.Unlock()
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		m.sendJobs(ctx)
+	}()
+
+	for _, w := range m.workers {
+		wg.Add(2)
+		go func(w *worker) {
+			defer wg.Done()
+			w.client.Run(ctx)
+		}(w)
+
+		go func(w *worker) {
+			defer wg.Done()
+			m.handleEvents(ctx, w)
+		}(w)
+	}
+
+	wg.Wait()
+	close(m.jobs)
+	return ctx.Err()
+}
+
+func (m *Manager) handleEvents(ctx context.Context, w *worker) {
+	for event := range w.client.Events() {
+		m.mutex.Lock()
+		switch event.Type {
+		case ConnectedEvent:
+			w.stats.Connected = true
+		case DisconnectedEvent:
+			w.stats.Connected = false
+			// responses of the pending shares are lost with the connection
+			w.pending = nil
+		case AcceptedEvent:
+			w.stats.Accepted++
+			if len(w.pending) > 0 {
+				w.stats.AcceptedDifficulty.Add(w.stats.AcceptedDifficulty, w.pending[0].Big())
+				w.pending = w.pending[1:]
+			}
+		case RejectedEvent:
+			w.stats.Rejected++
+			w.stats.RejectReasons[event.Reason]++
+			if len(w.pending) > 0 {
+				w.pending = w.pending[1:]
+			}
+		case JobEvent:
+			m.queueJob(w, event.Job)
+		}
+		m.mutex.Unlock()
+	}
+}
+
+// Must be called with the mutex locked.
+func (m *Manager) queueJob(w *worker, job BlockTemplate) {
+	work, err := job.MinerWork()
+	if err == nil {
+		jobDifficulty, _ := difficulty.NewFromString(job.Difficulty)
+		w.recent = append(w.recent, recentJob{workHash: work.WorkHash, difficulty: jobDifficulty})
+		if len(w.recent) > RECENT_JOBS {
+			w.recent = w.recent[1:]
+		}
+	}
+
+	if w.queued == nil {
+		m.queue = append(m.queue, w)
+	}
+	w.queued = &job
+
+	select {
+	case m.wake <- struct{}{}:
+	default:
+	}
+}
+
+// Send the queued jobs on Jobs. Events are never blocked by a slow reader.
+func (m *Manager) sendJobs(ctx context.Context) {
+	for {
+		m.mutex.Lock()
+		var job WorkerJob
+		ok := len(m.queue) > 0
+		if ok {
+			w := m.queue[0]
+			m.queue = m.queue[1:]
+			job = WorkerJob{Worker: w.config.Name, Job: *w.queued}
+			w.queued = nil
+		}
+		m.mutex.Unlock()
+
+		if !ok {
+			select {
+			case <-m.wake:
+				continue
+			case <-ctx.Done():
+				return
+			}
+		}
+
+		select {
+		case m.jobs <- job:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// Submit a share with the connection of the worker.
+// It is counted with the difficulty of the job it was made from, if that job is one of the last RECENT_JOBS of the worker.
+func (m *Manager) Submit(name string, work block.MinerWork) error {
+	w, ok := m.workers[name]
+	if !ok {
+		return fmt.Errorf("unknown worker %q", name)
+	}
+
+	// counted before sending, the response may come before SubmitMinerWork returns
+	m.mutex.Lock()
+	var jobDifficulty difficulty.Difficulty
+	for i := len(w.recent) - 1; i >= 0; i-- {
+		if w.recent[i].workHash == work.WorkHash {
+			jobDifficulty = w.recent[i].difficulty
+			break
+		}
+	}
+
+	w.stats.Submitted++
+	w.stats.LastShare = time.Now()
+	w.pending = append(w.pending, jobDifficulty)
+	m.mutex.Unlock()
+
+	err := w.client.SubmitMinerWork(work)
+	if err != nil {
+		m.mutex.Lock()
+		w.stats.Submitted--
+		if len(w.pending) > 0 {
+			w.pending = w.pending[:len(w.pending)-1]
+		}
+		m.mutex.Unlock()
+	}
+
+	return err
+}
+
+// Copy of the statistics of every worker, sorted by name.
+func (m *Manager) Stats() []WorkerStats {
+	defer m.mutex.Unlock()
+	m.mutex.Lock()
+
+	elapsed := time.Since(m.started).Seconds()
+	stats := make([]WorkerStats, 0, len(m.workers))
+	for _, w := range m.workers {
+		s := w.stats
+		s.RejectReasons = make(map[string]uint64, len(w.stats.RejectReasons))
+		for reason, count := range w.stats.RejectReasons {
+			s.RejectReasons[reason] = count
+		}
+
+		s.AcceptedDifficulty = new(big.Int).Set(w.stats.AcceptedDifficulty)
+		if !m.started.IsZero() && elapsed > 0 {
+			value, _ := new(big.Float).SetInt(s.AcceptedDifficulty).Float64()
+			s.Hashrate = value / elapsed
+		}
+
+		stats = append(stats, s)
+	}
+
+	sort.Slice(stats, func(i, j int) bool {
+		return stats[i].Name < stats[j].Name
+	})
+
+	return stats
+}
@@ -0,0 +1,180 @@
+package getwork
+
+import (
+	"context"
+	"fmt"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestManager(t *testing.T) {
+	_, endpoint := useServer(t)
+	manager, err := NewManager([]WorkerConfig{
+		{Name: "rig-1", Endpoint: endpoint, MinerAddress: TESTNET_WALLET},
+		{Name: "rig-2", Endpoint: endpoint, MinerAddress: TESTNET_WALLET},
+	}, ClientOptions{MinBackoff: 10 * time.Millisecond})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- manager.Run(ctx) }()
+
+	jobs := make(map[string]BlockTemplate)
+	for len(jobs) < 2 {
+		select {
+		case job := <-manager.Jobs():
+			jobs[job.Worker] = job.Job
+		case <-time.After(5 * time.Second):
+			t.Fatal("jobs were not received")
+		}
+	}
+
+	work, err := jobs["rig-1"].MinerWork()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = manager.Submit("rig-1", work)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	work.SetNonce(work.Nonce + 1)
+	err = manager.Submit("rig-1", work)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = manager.Submit("unknown", work)
+	if err == nil {
+		t.Error("Expected unknown worker error")
+	}
+
+	deadline := time.After(5 * time.Second)
+	for {
+		stats := manager.Stats()
+		if stats[0].Accepted+stats[0].Rejected == 2 {
+			if stats[0].Name != "rig-1" || stats[0].Submitted != 2 || stats[0].Accepted != 1 ||
+				stats[0].RejectReasons["invalid miner work"] != 1 || stats[0].AcceptedDifficulty.Int64() != 1000 || stats[0].Hashrate <= 0 {
+				t.Errorf("Unexpected stats %+v", stats[0])
+			}
+
+			if stats[1].Name != "rig-2" || !stats[1].Connected || stats[1].Submitted != 0 {
+				t.Errorf("Unexpected stats %+v", stats[1])
+			}
+
+			break
+		}
+
+		select {
+		case <-deadline:
+			t.Fatalf("shares were not counted %+v", stats)
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Run did not return")
+	}
+}
+
+// A slow reader of Jobs must not block the events of the worker, and only gets its latest job.
+// Shares are counted with the difficulty of the job they were made from.
+func TestManagerStaleJobs(t *testing.T) {
+	var height uint64
+	server := NewServer(ServerHooks{
+		NewJob: func(miner *Miner) (BlockTemplate, error) {
+			height := atomic.AddUint64(&height, 1)
+			return BlockTemplate{
+				Algorithm:  "xel/v2",
+				Difficulty: fmt.Sprint(height * 1000),
+				Height:     height,
+				Template:   fmt.Sprintf("%02x", height) + strings.Repeat("ab", 111),
+			}, nil
+		},
+		Submit: func(miner *Miner, params SubmitParams) error {
+			return nil
+		},
+	})
+
+	httpServer := httptest.NewServer(server)
+	defer httpServer.Close()
+	defer server.Close()
+
+	endpoint := "ws" + strings.TrimPrefix(httpServer.URL, "http") + "/getwork"
+	manager, err := NewManager([]WorkerConfig{
+		{Name: "rig", Endpoint: endpoint, MinerAddress: TESTNET_WALLET},
+	}, ClientOptions{MinBackoff: 10 * time.Millisecond})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go manager.Run(ctx)
+
+	first := <-manager.Jobs()
+	for i := 0; i < 5; i++ {
+		server.NotifyNewJob()
+	}
+
+	deadline := time.After(5 * time.Second)
+	for {
+		manager.mutex.Lock()
+		done := len(manager.workers["rig"].recent) == 6
+		manager.mutex.Unlock()
+
+		if done {
+			break
+		}
+
+		select {
+		case <-deadline:
+			t.Fatal("jobs were not received")
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+
+	// at most one older job was already being sent when the latest one arrived
+	for i := 0; ; i++ {
+		job := <-manager.Jobs()
+		if job.Job.Height == 6 {
+			break
+		}
+
+		if i > 0 {
+			t.Fatalf("Unexpected stale job %d", job.Job.Height)
+		}
+	}
+
+	work, err := first.Job.MinerWork()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = manager.Submit("rig", work)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	deadline = time.After(5 * time.Second)
+	for manager.Stats()[0].Accepted == 0 {
+		select {
+		case <-deadline:
+			t.Fatal("share was not accepted")
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+
+	if accepted := manager.Stats()[0].AcceptedDifficulty.Int64(); accepted != 1000 {
+		t.Errorf("Expected the difficulty of the first job, got %d", accepted)
+	}
+}
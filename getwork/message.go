@@ -0,0 +1,193 @@
+package getwork
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+var ErrInvalidMessage = errors.New("invalid getwork message")
+
+// Job sent by the daemon. Fields unknown to this version are kept in Extra.
+type NewJobMessage struct {
+	Algorithm  string `json:"algorithm"`
+	Difficulty string `json:"difficulty"`
+	Height     uint64 `json:"height"`
+	Topoheight uint64 `json:"topoheight,omitempty"`
+	MinerWork  string `json:"miner_work,omitempty"`
+	// Sent by older daemons instead of MinerWork.
+	Template string                     `json:"template,omitempty"`
+	Extra    map[string]json.RawMessage `json:"-"`
+}
+
+var newJobFields = []string{"algorithm", "difficulty", "height", "topoheight", "miner_work", "template"}
+
+func (m *NewJobMessage) UnmarshalJSON(data []byte) error {
+	type plain NewJobMessage
+	var value plain
+	err := json.Unmarshal(data, &value)
+	if err != nil {
+		return err
+	}
+
+	var fields map[string]json.RawMessage
+	err = json.Unmarshal(data, &fields)
+	if err != nil {
+		return err
+	}
+
+	for _, name := range newJobFields {
+		delete(fields, name)
+	}
+
+	value.Extra = nil
+	if len(fields) > 0 {
+		value.Extra = fields
+	}
+
+	*m = NewJobMessage(value)
+	return nil
+}
+
+// Same as json.Marshal without escaping HTML characters, so that raw values are sent back unchanged.
+func marshal(v interface{}) ([]byte, error) {
+	buf := new(bytes.Buffer)
+	encoder := json.NewEncoder(buf)
+	encoder.SetEscapeHTML(false)
+	err := encoder.Encode(v)
+	if err != nil {
+		return nil, err
+	}
+
+	return bytes.TrimSuffix(buf.Bytes(), []byte("\n")), nil
+}
+
+func (m NewJobMessage) MarshalJSON() ([]byte, error) {
+	type plain NewJobMessage
+	data, err := marshal(plain(m))
+	if err != nil || len(m.Extra) == 0 {
+		return data, err
+	}
+
+	var fields map[string]json.RawMessage
+	err = json.Unmarshal(data, &fields)
+	if err != nil {
+		return nil, err
+	}
+
+	for name, value := range m.Extra {
+		if _, ok := fields[name]; !ok {
+			fields[name] = value
+		}
+	}
+
+	return marshal(fields)
+}
+
+func (m NewJobMessage) BlockTemplate() BlockTemplate {
+	template := m.MinerWork
+	if template == "" {
+		template = m.Template
+	}
+
+	return BlockTemplate{
+		Algorithm:  m.Algorithm,
+		Difficulty: m.Difficulty,
+		Height:     m.Height,
+		Template:   template,
+	}
+}
+
+// Message sent by the daemon to miners. Type is NewJob, BlockAccepted, BlockRejected,
+// or for an unknown message its single key, or empty if it isn't an object with one key.
+// Raw always holds the message as received.
+type Message struct {
+	Type   string
+	Job    *NewJobMessage
+	Reason string
+	Raw    json.RawMessage
+}
+
+func (m Message) IsKnown() bool {
+	switch m.Type {
+	case NewJob, BlockAccepted, BlockRejected:
+		return true
+	}
+
+	return false
+}
+
+// Decode a message from the daemon. An unknown message is not an error, only a malformed known one is.
+func DecodeMessage(data []byte) (msg Message, err error) {
+	msg.Raw = append(json.RawMessage{}, data...)
+	if !json.Valid(data) {
+		err = fmt.Errorf("%w: not JSON", ErrInvalidMessage)
+		return
+	}
+
+	var value string
+	if json.Unmarshal(data, &value) == nil {
+		if value == BlockAccepted {
+			msg.Type = BlockAccepted
+		}
+
+		return
+	}
+
+	var fields map[string]json.RawMessage
+	if json.Unmarshal(data, &fields) != nil || len(fields) != 1 {
+		return
+	}
+
+	for name := range fields {
+		msg.Type = name
+	}
+
+	switch msg.Type {
+	case NewJob:
+		if bytes.Equal(bytes.TrimSpace(fields[NewJob]), []byte("null")) {
+			err = fmt.Errorf("%w: %s is null", ErrInvalidMessage, NewJob)
+			return
+		}
+
+		var job NewJobMessage
+		err = json.Unmarshal(fields[NewJob], &job)
+		if err != nil {
+			err = fmt.Errorf("%w: %s: %v", ErrInvalidMessage, NewJob, err)
+			return
+		}
+
+		msg.Job = &job
+	case BlockRejected:
+		err = json.Unmarshal(fields[BlockRejected], &msg.Reason)
+		if err != nil {
+			err = fmt.Errorf("%w: %s: %v", ErrInvalidMessage, BlockRejected, err)
+			return
+		}
+	}
+
+	return
+}
+
+// Encode a message as sent by the daemon. Unknown messages are sent as their Raw value.
+func EncodeMessage(msg Message) ([]byte, error) {
+	switch msg.Type {
+	case NewJob:
+		if msg.Job == nil {
+			return nil, fmt.Errorf("%w: %s without job", ErrInvalidMessage, NewJob)
+		}
+
+		return marshal(map[string]*NewJobMessage{NewJob: msg.Job})
+	case BlockAccepted:
+		return marshal(BlockAccepted)
+	case BlockRejected:
+		return marshal(map[string]string{BlockRejected: msg.Reason})
+	}
+
+	if !json.Valid(msg.Raw) {
+		return nil, fmt.Errorf("%w: unknown message without raw value", ErrInvalidMessage)
+	}
+
+	return msg.Raw, nil
+}
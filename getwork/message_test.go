@@ -0,0 +1,103 @@
+package getwork
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+var messageSamples = []string{
+	`{"new_job":{"algorithm":"xel/v2","difficulty":"1000","height":12,"topoheight":15,"miner_work":"abcd","pool_fee":1}}`,
+	`{"new_job":{"algorithm":"xel/v1","difficulty":"1000","height":12,"template":"abcd"}}`,
+	`"block_accepted"`,
+	`{"block_rejected":"invalid pow"}`,
+	`{"motd":"welcome"}`,
+	`"pong"`,
+	`[1,2,3]`,
+}
+
+func TestDecodeMessage(t *testing.T) {
+	msg, err := DecodeMessage([]byte(messageSamples[0]))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	job := msg.Job.BlockTemplate()
+	if msg.Type != NewJob || job.Template != "abcd" || job.Height != 12 || msg.Job.Topoheight != 15 || string(msg.Job.Extra["pool_fee"]) != "1" {
+		t.Errorf("Unexpected message %+v", msg)
+	}
+
+	msg, err = DecodeMessage([]byte(messageSamples[1]))
+	if err != nil || msg.Job.BlockTemplate().Template != "abcd" || msg.Job.Extra != nil {
+		t.Errorf("Unexpected message %+v %v", msg, err)
+	}
+
+	msg, err = DecodeMessage([]byte(messageSamples[3]))
+	if err != nil || msg.Type != BlockRejected || msg.Reason != "invalid pow" {
+		t.Errorf("Unexpected message %+v %v", msg, err)
+	}
+
+	msg, err = DecodeMessage([]byte(messageSamples[4]))
+	if err != nil || msg.Type != "motd" || msg.IsKnown() || string(msg.Raw) != messageSamples[4] {
+		t.Errorf("Unexpected message %+v %v", msg, err)
+	}
+
+	for _, invalid := range []string{`{"new_job":{"height":"12"}}`, `{"new_job":null}`, `{"block_rejected":1}`, `{`} {
+		_, err = DecodeMessage([]byte(invalid))
+		if !errors.Is(err, ErrInvalidMessage) {
+			t.Errorf("Expected error for %s", invalid)
+		}
+	}
+}
+
+func TestEncodeMessage(t *testing.T) {
+	for _, sample := range messageSamples {
+		msg, err := DecodeMessage([]byte(sample))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		data, err := EncodeMessage(msg)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var expected, actual interface{}
+		json.Unmarshal([]byte(sample), &expected)
+		json.Unmarshal(data, &actual)
+		if !reflect.DeepEqual(expected, actual) {
+			t.Errorf("Expected %s, got %s", sample, data)
+		}
+	}
+}
+
+func FuzzDecodeMessage(f *testing.F) {
+	for _, sample := range messageSamples {
+		f.Add([]byte(sample))
+	}
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		msg, err := DecodeMessage(data)
+		if err != nil {
+			return
+		}
+
+		encoded, err := EncodeMessage(msg)
+		if err != nil {
+			if msg.IsKnown() {
+				t.Fatalf("Failed to encode %q: %v", data, err)
+			}
+			return
+		}
+
+		decoded, err := DecodeMessage(encoded)
+		if err != nil {
+			t.Fatalf("Failed to decode %q encoded from %q: %v", encoded, data, err)
+		}
+
+		if decoded.Type != msg.Type || decoded.Reason != msg.Reason || !reflect.DeepEqual(decoded.Job, msg.Job) {
+			t.Fatalf("Round trip of %q gave %+v, expected %+v", data, decoded, msg)
+		}
+	})
+}
@@ -0,0 +1,210 @@
+package getwork
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/gorilla/websocket"
+	"github.com/xelis-project/xelis-go-sdk/address"
+)
+
+// Miner connected to a Server, from the /{address}/{worker} path it connected to.
+type Miner struct {
+	Address    string
+	Worker     string
+	RemoteAddr string
+
+	conn  *websocket.Conn
+	mutex sync.Mutex
+	job   *BlockTemplate
+}
+
+// Last job sent to the miner. False if none was sent yet.
+func (m *Miner) Job() (BlockTemplate, bool) {
+	defer m.mutex.Unlock()
+	m.mutex.Lock()
+
+	if m.job == nil {
+		return BlockTemplate{}, false
+	}
+
+	return *m.job, true
+}
+
+// Must be called with the mutex locked.
+func (m *Miner) write(msg Message) error {
+	data, err := EncodeMessage(msg)
+	if err != nil {
+		return err
+	}
+
+	return m.conn.WriteMessage(websocket.TextMessage, data)
+}
+
+func (m *Miner) send(msg Message) error {
+	defer m.mutex.Unlock()
+	m.mutex.Lock()
+	return m.write(msg)
+}
+
+func (m *Miner) sendJob(job BlockTemplate) error {
+	defer m.mutex.Unlock()
+	m.mutex.Lock()
+
+	err := m.write(Message{Type: NewJob, Job: &NewJobMessage{
+		Algorithm:  job.Algorithm,
+		Difficulty: job.Difficulty,
+		Height:     job.Height,
+		MinerWork:  job.Template,
+	}})
+	if err != nil {
+		return err
+	}
+
+	m.job = &job
+	return nil
+}
+
+type ServerHooks struct {
+	// Job of a miner, called when it connects and for every miner on NotifyNewJob. Required.
+	// An error when the miner connects closes its connection.
+	NewJob func(miner *Miner) (BlockTemplate, error)
+	// Check a block submitted by a miner, for example forward it to the daemon.
+	// Nil accepts it, an error rejects it with the error message as reason.
+	Submit func(miner *Miner, params SubmitParams) error
+}
+
+// Server speaks the getwork protocol of the daemon /getwork endpoint, to run a proxy or a pool in front of it.
+// It is an http.Handler serving miners connecting to {path}/{address}/{worker}.
+type Server struct {
+	hooks    ServerHooks
+	upgrader websocket.Upgrader
+	mutex    sync.Mutex
+	miners   map[*Miner]bool
+}
+
+func NewServer(hooks ServerHooks) *Server {
+	return &Server{
+		hooks:  hooks,
+		miners: make(map[*Miner]bool),
+	}
+}
+
+var ErrInvalidSubmit = errors.New("invalid submit message")
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	if len(parts) < 2 {
+		http.Error(w, "Expected /{address}/{worker}", http.StatusBadRequest)
+		return
+	}
+
+	miner := &Miner{
+		Address:    parts[len(parts)-2],
+		Worker:     parts[len(parts)-1],
+		RemoteAddr: r.RemoteAddr,
+	}
+
+	valid, _ := address.IsValidAddress(miner.Address)
+	if !valid || miner.Worker == "" {
+		http.Error(w, "Invalid miner address or worker", http.StatusBadRequest)
+		return
+	}
+
+	conn, err := s.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		return
+	}
+	miner.conn = conn
+
+	s.mutex.Lock()
+	s.miners[miner] = true
+	s.mutex.Unlock()
+
+	defer func() {
+		s.mutex.Lock()
+		delete(s.miners, miner)
+		s.mutex.Unlock()
+		conn.Close()
+	}()
+
+	job, err := s.hooks.NewJob(miner)
+	if err != nil {
+		return
+	}
+
+	err = miner.sendJob(job)
+	if err != nil {
+		return
+	}
+
+	for {
+		_, msg, err := conn.ReadMessage()
+		if err != nil {
+			return
+		}
+
+		err = s.submit(miner, msg)
+		if err != nil {
+			err = miner.send(Message{Type: BlockRejected, Reason: err.Error()})
+		} else {
+			err = miner.send(Message{Type: BlockAccepted})
+		}
+
+		if err != nil {
+			return
+		}
+	}
+}
+
+func (s *Server) submit(miner *Miner, msg []byte) error {
+	var params SubmitParams
+	err := json.Unmarshal(msg, &params)
+	if err != nil || params.BlockTemplate == "" {
+		return ErrInvalidSubmit
+	}
+
+	if s.hooks.Submit == nil {
+		return nil
+	}
+
+	return s.hooks.Submit(miner, params)
+}
+
+// Send a new job to every connected miner, for example when the daemon has a new block.
+// Miners whose job can't be generated keep their previous one.
+func (s *Server) NotifyNewJob() {
+	for _, miner := range s.Miners() {
+		job, err := s.hooks.NewJob(miner)
+		if err != nil {
+			continue
+		}
+
+		err = miner.sendJob(job)
+		if err != nil {
+			miner.conn.Close()
+		}
+	}
+}
+
+func (s *Server) Miners() []*Miner {
+	defer s.mutex.Unlock()
+	s.mutex.Lock()
+
+	miners := make([]*Miner, 0, len(s.miners))
+	for miner := range s.miners {
+		miners = append(miners, miner)
+	}
+
+	return miners
+}
+
+// Disconnect every miner. Stop the HTTP server serving it first so that no new miner connects.
+func (s *Server) Close() {
+	for _, miner := range s.Miners() {
+		miner.conn.Close()
+	}
+}
@@ -0,0 +1,113 @@
+package getwork
+
+import (
+	"errors"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func useServer(t *testing.T) (server *Server, endpoint string) {
+	var height uint64
+	server = NewServer(ServerHooks{
+		NewJob: func(miner *Miner) (BlockTemplate, error) {
+			return BlockTemplate{
+				Algorithm:  "xel/v2",
+				Difficulty: "1000",
+				Height:     atomic.AddUint64(&height, 1),
+				Template:   strings.Repeat("ab", 112),
+			}, nil
+		},
+		Submit: func(miner *Miner, params SubmitParams) error {
+			job, ok := miner.Job()
+			if !ok || params.BlockTemplate != job.Template {
+				return errors.New("invalid miner work")
+			}
+
+			return nil
+		},
+	})
+
+	httpServer := httptest.NewServer(server)
+	t.Cleanup(func() {
+		server.Close()
+		httpServer.Close()
+	})
+
+	endpoint = "ws" + strings.TrimPrefix(httpServer.URL, "http") + "/getwork"
+	return
+}
+
+func receiveJob(t *testing.T, getwork *Getwork) BlockTemplate {
+	select {
+	case job := <-getwork.Job:
+		return job
+	case err := <-getwork.Err:
+		t.Fatal(err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("new_job was not received")
+	}
+
+	return BlockTemplate{}
+}
+
+func TestServer(t *testing.T) {
+	server, endpoint := useServer(t)
+	getwork, err := NewGetwork(endpoint, TESTNET_WALLET, "rig-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(getwork.Close)
+
+	job := receiveJob(t, getwork)
+	if job.Height != 1 || job.Algorithm != "xel/v2" || job.Difficulty != "1000" || len(job.Template) != 224 {
+		t.Errorf("Unexpected job %+v", job)
+	}
+
+	miners := server.Miners()
+	if len(miners) != 1 || miners[0].Address != TESTNET_WALLET || miners[0].Worker != "rig-1" {
+		t.Fatalf("Unexpected miners %+v", miners)
+	}
+
+	err = getwork.SubmitBlock(job.Template)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case <-getwork.AcceptedBlock:
+	case <-time.After(5 * time.Second):
+		t.Fatal("block_accepted was not received")
+	}
+
+	err = getwork.SubmitBlock("00")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case reason := <-getwork.RejectedBlock:
+		if reason != "invalid miner work" {
+			t.Errorf("Unexpected reason %s", reason)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("block_rejected was not received")
+	}
+
+	go server.NotifyNewJob()
+	job = receiveJob(t, getwork)
+	if job.Height != 2 {
+		t.Errorf("Expected a job at height 2, got %+v", job)
+	}
+}
+
+func TestServerInvalidMiner(t *testing.T) {
+	_, endpoint := useServer(t)
+
+	_, err := NewGetwork(endpoint, "invalid", "rig-1")
+	if err == nil {
+		t.Error("Expected invalid address error")
+	}
+}
@@ -1,5 +1,7 @@
 package getwork
 
+import "github.com/xelis-project/xelis-go-sdk/block"
+
 const (
 	NewJob        string = `new_job`
 	BlockAccepted string = `block_accepted`
@@ -14,3 +16,15 @@ type BlockTemplate struct {
 	Height     uint64 `json:"height"`
 	Template   string `json:"template"`
 }
+
+// Miner work of the job. Once changed, submit it with MinerWork.EncodeHex.
+func (t BlockTemplate) MinerWork() (block.MinerWork, error) {
+	return block.DecodeMinerWorkHex(t.Template)
+}
+
+// Message sent by miners to submit a block.
+// MinerWork is set when BlockTemplate is a full block header and not the miner work of the job.
+type SubmitParams struct {
+	BlockTemplate string  `json:"block_template"`
+	MinerWork     *string `json:"miner_work,omitempty"`
+}
@@ -2,7 +2,17 @@ module github.com/xelis-project/xelis-go-sdk
 
 go 1.19
 
-require github.com/creachadair/jrpc2 v0.44.0
+require (
+	github.com/creachadair/jrpc2 v0.44.0
+	github.com/gtank/ristretto255 v0.1.2
+	github.com/zeebo/blake3 v0.2.3
+	golang.org/x/crypto v0.14.0
+)
+
+require (
+	github.com/klauspost/cpuid/v2 v2.0.12 // indirect
+	golang.org/x/sys v0.13.0 // indirect
+)
 
 require (
 	github.com/gorilla/websocket v1.5.0
@@ -0,0 +1,119 @@
+// Package keys generates XELIS key pairs and derives their public key and address.
+//
+// A private key is a non-zero Ristretto scalar s, and its public key is the compressed point s⁻¹·H,
+// where H is the Pedersen blinding generator, the point hashed with SHA3-512 from the compressed Ristretto basepoint.
+package keys
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+
+	"github.com/gtank/ristretto255"
+	"github.com/xelis-project/xelis-go-sdk/address"
+	"golang.org/x/crypto/sha3"
+)
+
+const KEY_SIZE = 32
+
+var ErrZeroPrivateKey = errors.New("private key cannot be zero")
+
+var h = blindingGenerator()
+
+func blindingGenerator() *ristretto255.Element {
+	basepoint := ristretto255.NewElement().Base().Encode(nil)
+	hash := sha3.Sum512(basepoint)
+	return ristretto255.NewElement().FromUniformBytes(hash[:])
+}
+
+type PrivateKey struct {
+	scalar *ristretto255.Scalar
+}
+
+// Compressed Ristretto point.
+type PublicKey [KEY_SIZE]byte
+
+func GeneratePrivateKey() (PrivateKey, error) {
+	for {
+		data := make([]byte, 64)
+		_, err := rand.Read(data)
+		if err != nil {
+			return PrivateKey{}, err
+		}
+
+		scalar := ristretto255.NewScalar().FromUniformBytes(data)
+		if scalar.Equal(ristretto255.NewScalar()) == 0 {
+			return PrivateKey{scalar: scalar}, nil
+		}
+	}
+}
+
+// Private key from its canonical 32 bytes little-endian encoding.
+func NewPrivateKey(data []byte) (PrivateKey, error) {
+	if len(data) != KEY_SIZE {
+		return PrivateKey{}, fmt.Errorf("invalid private key size %d", len(data))
+	}
+
+	scalar := ristretto255.NewScalar()
+	err := scalar.Decode(data)
+	if err != nil {
+		return PrivateKey{}, err
+	}
+
+	if scalar.Equal(ristretto255.NewScalar()) == 1 {
+		return PrivateKey{}, ErrZeroPrivateKey
+	}
+
+	return PrivateKey{scalar: scalar}, nil
+}
+
+func NewPrivateKeyFromHex(value string) (PrivateKey, error) {
+	data, err := hex.DecodeString(value)
+	if err != nil {
+		return PrivateKey{}, err
+	}
+
+	return NewPrivateKey(data)
+}
+
+func (k PrivateKey) Bytes() []byte {
+	return k.scalar.Encode(nil)
+}
+
+func (k PrivateKey) Hex() string {
+	return hex.EncodeToString(k.Bytes())
+}
+
+func (k PrivateKey) PublicKey() (publicKey PublicKey) {
+	inverse := ristretto255.NewScalar().Invert(k.scalar)
+	point := ristretto255.NewElement().ScalarMult(inverse, h)
+	copy(publicKey[:], point.Encode(nil))
+	return
+}
+
+func (k PrivateKey) Address(mainnet bool) *address.Address {
+	publicKey := k.PublicKey()
+	return publicKey.Address(mainnet)
+}
+
+// Public key of an address.
+func NewPublicKey(addr *address.Address) (publicKey PublicKey, err error) {
+	data := addr.GetPublicKey()
+	if len(data) != KEY_SIZE {
+		err = fmt.Errorf("invalid public key size %d", len(data))
+		return
+	}
+
+	copy(publicKey[:], data)
+	return
+}
+
+func (p PublicKey) Hex() string {
+	return hex.EncodeToString(p[:])
+}
+
+func (p PublicKey) Address(mainnet bool) *address.Address {
+	addr, _ := address.NewAddress(p[:], mainnet)
+	return addr
+}
@@ -0,0 +1,94 @@
+package keys
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/gtank/ristretto255"
+	"github.com/xelis-project/xelis-go-sdk/address"
+)
+
+func TestGenerate(t *testing.T) {
+	privateKey, err := GeneratePrivateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	publicKey := privateKey.PublicKey()
+
+	// s * (s⁻¹ * H) = H
+	point := ristretto255.NewElement()
+	err = point.Decode(publicKey[:])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if ristretto255.NewElement().ScalarMult(privateKey.scalar, point).Equal(h) != 1 {
+		t.Error("Public key is not derived from the private key")
+	}
+
+	decoded, err := NewPrivateKeyFromHex(privateKey.Hex())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if decoded.PublicKey() != publicKey {
+		t.Error("Decoded private key has another public key")
+	}
+
+	for _, mainnet := range []bool{true, false} {
+		addr, err := privateKey.Address(mainnet).Format()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		parsed, err := address.NewAddressFromString(addr)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if parsed.IsMainnet() != mainnet || !bytes.Equal(parsed.GetPublicKey(), publicKey[:]) {
+			t.Errorf("Unexpected address %s", addr)
+		}
+
+		fromAddress, err := NewPublicKey(parsed)
+		if err != nil || fromAddress != publicKey {
+			t.Errorf("Unexpected public key %s from address", fromAddress.Hex())
+		}
+	}
+}
+
+func TestInvalidPrivateKey(t *testing.T) {
+	_, err := NewPrivateKey(make([]byte, KEY_SIZE))
+	if err != ErrZeroPrivateKey {
+		t.Errorf("Expected zero private key error, got %v", err)
+	}
+
+	// above the group order
+	_, err = NewPrivateKey(bytes.Repeat([]byte{0xFF}, KEY_SIZE))
+	if err == nil {
+		t.Error("Expected non canonical private key error")
+	}
+
+	_, err = NewPrivateKey(make([]byte, 31))
+	if err == nil {
+		t.Error("Expected invalid size error")
+	}
+}
+
+// The public key of the private key 1 is H itself, the blinding generator of the
+// bulletproofs Pedersen generators also used by the node.
+func TestPublicKeyOfOne(t *testing.T) {
+	one := make([]byte, KEY_SIZE)
+	one[0] = 1
+
+	privateKey, err := NewPrivateKey(one)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := "8c9240b456a9e6dc65c377a1048d745f94a08cdb7f44cbcd7b46f34048871134"
+	if publicKey := privateKey.PublicKey().Hex(); publicKey != expected {
+		t.Errorf("Expected %s, got %s", expected, publicKey)
+	}
+}
@@ -0,0 +1,126 @@
+package keys
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+
+	"github.com/gtank/ristretto255"
+	"github.com/xelis-project/xelis-go-sdk/address"
+	"golang.org/x/crypto/sha3"
+)
+
+const SIGNATURE_SIZE = 64
+
+var ErrInvalidSignature = errors.New("invalid signature")
+
+// Schnorr signature (s, e) over the H generator, as created by the wallet sign_data method.
+type Signature struct {
+	s *ristretto255.Scalar
+	e *ristretto255.Scalar
+}
+
+// Signature from its 64 bytes encoding, s then e.
+func NewSignature(data []byte) (Signature, error) {
+	if len(data) != SIGNATURE_SIZE {
+		return Signature{}, fmt.Errorf("invalid signature size %d", len(data))
+	}
+
+	s, e := ristretto255.NewScalar(), ristretto255.NewScalar()
+	err := s.Decode(data[:32])
+	if err != nil {
+		return Signature{}, err
+	}
+
+	err = e.Decode(data[32:])
+	if err != nil {
+		return Signature{}, err
+	}
+
+	return Signature{s: s, e: e}, nil
+}
+
+func NewSignatureFromHex(value string) (Signature, error) {
+	data, err := hex.DecodeString(value)
+	if err != nil {
+		return Signature{}, err
+	}
+
+	return NewSignature(data)
+}
+
+func (s Signature) Bytes() []byte {
+	return s.e.Encode(s.s.Encode(nil))
+}
+
+func (s Signature) Hex() string {
+	return hex.EncodeToString(s.Bytes())
+}
+
+// Scalar from the SHA3-512 of the public key, the message and the point.
+func hashAndPointToScalar(publicKey PublicKey, message []byte, point *ristretto255.Element) *ristretto255.Scalar {
+	hasher := sha3.New512()
+	hasher.Write(publicKey[:])
+	hasher.Write(message)
+	hasher.Write(point.Encode(nil))
+	return ristretto255.NewScalar().FromUniformBytes(hasher.Sum(nil))
+}
+
+func (k PrivateKey) Sign(message []byte) (Signature, error) {
+	data := make([]byte, 64)
+	_, err := rand.Read(data)
+	if err != nil {
+		return Signature{}, err
+	}
+
+	nonce := ristretto255.NewScalar().FromUniformBytes(data)
+	r := ristretto255.NewElement().ScalarMult(nonce, h)
+	e := hashAndPointToScalar(k.PublicKey(), message, r)
+
+	// s = private⁻¹ * e + nonce
+	s := ristretto255.NewScalar().Invert(k.scalar)
+	s.Multiply(s, e).Add(s, nonce)
+	return Signature{s: s, e: e}, nil
+}
+
+func (p PublicKey) Verify(message []byte, signature Signature) bool {
+	point := ristretto255.NewElement()
+	if point.Decode(p[:]) != nil {
+		return false
+	}
+
+	// s*H - e*P is the nonce point of the signer
+	r := ristretto255.NewElement().ScalarMult(signature.s, h)
+	r.Subtract(r, ristretto255.NewElement().ScalarMult(signature.e, point))
+	return hashAndPointToScalar(p, message, r).Equal(signature.e) == 1
+}
+
+// Check a signature returned by the wallet SignData method for data, signed by the key of addr.
+// The wallet signs the binary encoding of data, so its fields must be in the order sent to the wallet (see DataElement.Keys)
+// and its values of the types the wallet read, the smallest number type when it came from JSON.
+func VerifySignData(data address.DataElement, signature string, addr *address.Address) error {
+	publicKey, err := NewPublicKey(addr)
+	if err != nil {
+		return err
+	}
+
+	sig, err := NewSignatureFromHex(signature)
+	if err != nil {
+		return err
+	}
+
+	var message bytes.Buffer
+	writer := address.DataValueWriter{Writer: &message}
+	err = writer.Write(data)
+	if err != nil {
+		return err
+	}
+
+	if !publicKey.Verify(message.Bytes(), sig) {
+		return ErrInvalidSignature
+	}
+
+	return nil
+}
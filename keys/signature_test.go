@@ -0,0 +1,79 @@
+package keys
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/xelis-project/xelis-go-sdk/address"
+)
+
+func TestSignature(t *testing.T) {
+	privateKey, err := GeneratePrivateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	publicKey := privateKey.PublicKey()
+	signature, err := privateKey.Sign([]byte("hello"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	decoded, err := NewSignatureFromHex(signature.Hex())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.Equal(decoded.Bytes(), signature.Bytes()) || !publicKey.Verify([]byte("hello"), decoded) {
+		t.Error("Expected a valid signature")
+	}
+
+	if publicKey.Verify([]byte("hellO"), signature) {
+		t.Error("Expected an invalid signature for another message")
+	}
+
+	other, err := GeneratePrivateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if other.PublicKey().Verify([]byte("hello"), signature) {
+		t.Error("Expected an invalid signature for another key")
+	}
+
+	_, err = NewSignature(make([]byte, 63))
+	if err == nil {
+		t.Error("Expected invalid size error")
+	}
+}
+
+func TestVerifySignData(t *testing.T) {
+	privateKey, err := GeneratePrivateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data := address.DataElement{Array: []address.DataElement{{Value: "login"}, {Value: uint64(1700000000)}}}
+	var message bytes.Buffer
+	writer := address.DataValueWriter{Writer: &message}
+	err = writer.Write(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	signature, err := privateKey.Sign(message.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = VerifySignData(data, signature.Hex(), privateKey.Address(false))
+	if err != nil {
+		t.Error(err)
+	}
+
+	data.Array[0].Value = "logout"
+	err = VerifySignData(data, signature.Hex(), privateKey.Address(false))
+	if err != ErrInvalidSignature {
+		t.Errorf("Expected invalid signature error, got %v", err)
+	}
+}
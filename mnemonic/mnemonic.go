@@ -0,0 +1,181 @@
+// Package mnemonic converts private keys to and from the recovery seeds of the XELIS wallet.
+//
+// A seed is 24 words encoding the 32 bytes of the private key, 4 bytes per 3 words,
+// followed by a checksum word picked with the CRC32 of the word prefixes.
+//
+// No word list is built in. Load the lists of the XELIS wallet with NewWordList
+// and register them with RegisterWordList before calling Decode.
+package mnemonic
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"strings"
+	"sync"
+
+	"github.com/xelis-project/xelis-go-sdk/keys"
+)
+
+// Number of words in a word list.
+const WORDS_LIST_SIZE = 1626
+
+// Number of words encoding the key, without the checksum word.
+const SEED_LENGTH = 24
+
+var ErrInvalidChecksum = errors.New("invalid seed checksum")
+var ErrUnknownLanguage = errors.New("no registered word list contains every word of the seed")
+
+type WordList struct {
+	Name string
+	// Number of characters identifying a word, used for the checksum.
+	PrefixLength int
+	Words        []string
+
+	indexes map[string]int
+}
+
+func NewWordList(name string, prefixLength int, words []string) (*WordList, error) {
+	if len(words) != WORDS_LIST_SIZE {
+		return nil, fmt.Errorf("word list %s has %d words, expected %d", name, len(words), WORDS_LIST_SIZE)
+	}
+
+	if prefixLength <= 0 {
+		return nil, fmt.Errorf("invalid prefix length %d", prefixLength)
+	}
+
+	list := &WordList{
+		Name:         name,
+		PrefixLength: prefixLength,
+		Words:        append([]string{}, words...),
+		indexes:      make(map[string]int, len(words)),
+	}
+
+	for i, word := range words {
+		if _, ok := list.indexes[word]; ok {
+			return nil, fmt.Errorf("duplicate word %s in word list %s", word, name)
+		}
+
+		list.indexes[word] = i
+	}
+
+	return list, nil
+}
+
+func (l *WordList) prefix(word string) string {
+	runes := []rune(word)
+	if len(runes) > l.PrefixLength {
+		runes = runes[:l.PrefixLength]
+	}
+
+	return string(runes)
+}
+
+func (l *WordList) checksumIndex(words []string) int {
+	var prefixes strings.Builder
+	for _, word := range words {
+		prefixes.WriteString(l.prefix(word))
+	}
+
+	return int(crc32.ChecksumIEEE([]byte(prefixes.String())) % SEED_LENGTH)
+}
+
+var (
+	mutex     sync.RWMutex
+	wordLists []*WordList
+)
+
+// Register a word list used by Decode to detect the language of a seed.
+func RegisterWordList(list *WordList) {
+	defer mutex.Unlock()
+	mutex.Lock()
+	wordLists = append(wordLists, list)
+}
+
+// Seed of 25 words of the private key.
+func Encode(privateKey keys.PrivateKey, list *WordList) []string {
+	return encodeBytes(privateKey.Bytes(), list)
+}
+
+func encodeBytes(data []byte, list *WordList) []string {
+	n := uint32(WORDS_LIST_SIZE)
+	words := make([]string, 0, SEED_LENGTH+1)
+	for i := 0; i < len(data); i += 4 {
+		value := binary.LittleEndian.Uint32(data[i : i+4])
+		w1 := value % n
+		w2 := (value/n + w1) % n
+		w3 := (value/n/n + w2) % n
+		words = append(words, list.Words[w1], list.Words[w2], list.Words[w3])
+	}
+
+	return append(words, words[list.checksumIndex(words)])
+}
+
+// Private key of a seed, in the language of the word list.
+func DecodeWithWordList(words []string, list *WordList) (privateKey keys.PrivateKey, err error) {
+	if len(words) != SEED_LENGTH+1 {
+		err = fmt.Errorf("invalid seed of %d words, expected %d", len(words), SEED_LENGTH+1)
+		return
+	}
+
+	checksum := list.checksumIndex(words[:SEED_LENGTH])
+	if list.prefix(words[checksum]) != list.prefix(words[SEED_LENGTH]) {
+		err = ErrInvalidChecksum
+		return
+	}
+
+	n := uint64(WORDS_LIST_SIZE)
+	data := make([]byte, 0, keys.KEY_SIZE)
+	for i := 0; i < SEED_LENGTH; i += 3 {
+		var indexes [3]uint64
+		for j := range indexes {
+			index, ok := list.indexes[words[i+j]]
+			if !ok {
+				err = fmt.Errorf("unknown word %s in word list %s", words[i+j], list.Name)
+				return
+			}
+
+			indexes[j] = uint64(index)
+		}
+
+		value := indexes[0] + n*((n-indexes[0]+indexes[1])%n) + n*n*((n-indexes[1]+indexes[2])%n)
+		if value%n != indexes[0] || value > 0xFFFFFFFF {
+			err = fmt.Errorf("invalid words at position %d", i)
+			return
+		}
+
+		data = binary.LittleEndian.AppendUint32(data, uint32(value))
+	}
+
+	return keys.NewPrivateKey(data)
+}
+
+// Private key of a seed, with the language detected from the registered word lists.
+func Decode(words []string) (keys.PrivateKey, *WordList, error) {
+	mutex.RLock()
+	lists := append([]*WordList{}, wordLists...)
+	mutex.RUnlock()
+
+	for _, list := range lists {
+		known := true
+		for _, word := range words {
+			if _, ok := list.indexes[word]; !ok {
+				known = false
+				break
+			}
+		}
+
+		if known {
+			privateKey, err := DecodeWithWordList(words, list)
+			return privateKey, list, err
+		}
+	}
+
+	return keys.PrivateKey{}, nil, ErrUnknownLanguage
+}
+
+// Same as Decode with the words of a seed separated by spaces.
+func DecodeString(seed string) (keys.PrivateKey, *WordList, error) {
+	return Decode(strings.Fields(seed))
+}
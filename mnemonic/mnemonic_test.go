@@ -0,0 +1,117 @@
+package mnemonic
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/xelis-project/xelis-go-sdk/keys"
+)
+
+// Word list with distinct 3 letters prefixes, not a real language.
+func testWordList(t *testing.T, name string) *WordList {
+	words := make([]string, WORDS_LIST_SIZE)
+	for i := range words {
+		words[i] = fmt.Sprintf("%c%c%c%s%d", 'a'+i%26, 'a'+i/26%26, 'a'+i/676, name, i)
+	}
+
+	list, err := NewWordList(name, 3, words)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return list
+}
+
+// Registers the lists for the test only, Decode sees the previous lists after it.
+func registerTestWordLists(t *testing.T, lists ...*WordList) {
+	mutex.Lock()
+	previous := wordLists
+	wordLists = append(wordLists[:len(wordLists):len(wordLists)], lists...)
+	mutex.Unlock()
+
+	t.Cleanup(func() {
+		defer mutex.Unlock()
+		mutex.Lock()
+		wordLists = previous
+	})
+}
+
+func TestRoundTrip(t *testing.T) {
+	list := testWordList(t, "test")
+	privateKey, err := keys.GeneratePrivateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	words := Encode(privateKey, list)
+	if len(words) != SEED_LENGTH+1 {
+		t.Fatalf("Expected %d words, got %d", SEED_LENGTH+1, len(words))
+	}
+
+	decoded, err := DecodeWithWordList(words, list)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if decoded.PublicKey() != privateKey.PublicKey() {
+		t.Error("Decoded seed has another public key")
+	}
+
+	registerTestWordLists(t, testWordList(t, "other"), list)
+	decoded, detected, err := DecodeString(strings.Join(words, " "))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if detected != list || !bytes.Equal(decoded.Bytes(), privateKey.Bytes()) {
+		t.Errorf("Unexpected word list %s or key", detected.Name)
+	}
+}
+
+func TestInvalidSeed(t *testing.T) {
+	list := testWordList(t, "test")
+	privateKey, err := keys.GeneratePrivateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	words := Encode(privateKey, list)
+
+	checksum := words[SEED_LENGTH]
+	for _, word := range list.Words {
+		if list.prefix(word) != list.prefix(checksum) {
+			words[SEED_LENGTH] = word
+			break
+		}
+	}
+
+	_, err = DecodeWithWordList(words, list)
+	if !errors.Is(err, ErrInvalidChecksum) {
+		t.Errorf("Expected invalid checksum error, got %v", err)
+	}
+
+	_, err = DecodeWithWordList(words[:SEED_LENGTH], list)
+	if err == nil {
+		t.Error("Expected invalid length error")
+	}
+
+	// encodes a value above the scalar group order
+	words = encodeBytes(bytes.Repeat([]byte{0xFF}, keys.KEY_SIZE), list)
+	_, err = DecodeWithWordList(words, list)
+	if err == nil {
+		t.Error("Expected non canonical private key error")
+	}
+
+	_, _, err = Decode(append(words[:SEED_LENGTH], "unknown"))
+	if !errors.Is(err, ErrUnknownLanguage) {
+		t.Errorf("Expected unknown language error, got %v", err)
+	}
+
+	_, err = NewWordList("short", 3, list.Words[1:])
+	if err == nil {
+		t.Error("Expected invalid word list size error")
+	}
+}
@@ -0,0 +1,73 @@
+// Package pow computes the proof-of-work hash of a miner work for the algorithm named in a block template.
+//
+// No algorithm is built in. Register xel/v1 and xel/v2 implementations,
+// like bindings to the reference xelis-hash crate, before hashing.
+package pow
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/xelis-project/xelis-go-sdk/block"
+)
+
+// Names used in the algorithm field of block templates and getwork jobs.
+const (
+	XelisHashV1 = "xel/v1"
+	XelisHashV2 = "xel/v2"
+)
+
+var ErrUnsupportedAlgorithm = errors.New("unsupported proof-of-work algorithm")
+
+// Proof-of-work hash of a serialized miner work of block.MINER_WORK_SIZE bytes.
+type HashFunc func(minerWork []byte) (block.Hash, error)
+
+var (
+	mutex      sync.RWMutex
+	algorithms = make(map[string]HashFunc)
+)
+
+// Register the implementation of an algorithm. It replaces any previous one.
+func Register(algorithm string, fn HashFunc) {
+	defer mutex.Unlock()
+	mutex.Lock()
+
+	if fn == nil {
+		delete(algorithms, algorithm)
+		return
+	}
+
+	algorithms[algorithm] = fn
+}
+
+func IsSupported(algorithm string) bool {
+	defer mutex.RUnlock()
+	mutex.RLock()
+
+	_, ok := algorithms[algorithm]
+	return ok
+}
+
+func Hash(algorithm string, minerWork []byte) (hash block.Hash, err error) {
+	if len(minerWork) != block.MINER_WORK_SIZE {
+		err = fmt.Errorf("invalid miner work size %d, expected %d", len(minerWork), block.MINER_WORK_SIZE)
+		return
+	}
+
+	mutex.RLock()
+	fn, ok := algorithms[algorithm]
+	mutex.RUnlock()
+
+	if !ok {
+		err = fmt.Errorf("%w: %s", ErrUnsupportedAlgorithm, algorithm)
+		return
+	}
+
+	return fn(minerWork)
+}
+
+// Proof-of-work hash of a decoded miner work, like the template of a getwork job.
+func HashMinerWork(algorithm string, work block.MinerWork) (block.Hash, error) {
+	return Hash(algorithm, work.Encode())
+}
@@ -0,0 +1,40 @@
+package pow
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/xelis-project/xelis-go-sdk/block"
+	"github.com/zeebo/blake3"
+)
+
+func TestRegister(t *testing.T) {
+	work := block.MinerWork{Nonce: 1}
+	_, err := HashMinerWork("test", work)
+	if !errors.Is(err, ErrUnsupportedAlgorithm) {
+		t.Errorf("Expected unsupported algorithm error, got %v", err)
+	}
+
+	Register("test", func(minerWork []byte) (block.Hash, error) {
+		return blake3.Sum256(minerWork), nil
+	})
+	t.Cleanup(func() { Register("test", nil) })
+
+	if !IsSupported("test") {
+		t.Fatal("Expected algorithm to be supported")
+	}
+
+	hash, err := HashMinerWork("test", work)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if hash != work.Hash() {
+		t.Errorf("Unexpected hash %s", hash)
+	}
+
+	_, err = Hash("test", make([]byte, 10))
+	if err == nil {
+		t.Error("Expected invalid size error")
+	}
+}
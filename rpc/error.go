@@ -0,0 +1,64 @@
+package rpc
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/creachadair/jrpc2"
+)
+
+// JSON-RPC 2.0 error codes used by the daemon and the wallet.
+// Chain failures (unknown block, account, transaction, ...) use InternalErrorCode and are told apart by their message,
+// see the sentinels of the daemon and wallet packages.
+const (
+	ParseErrorCode     = -32700
+	InvalidRequestCode = -32600
+	MethodNotFoundCode = -32601
+	InvalidParamsCode  = -32602
+	InternalErrorCode  = -32603
+)
+
+// Sentinels matching any error with the same code. Use them with errors.Is.
+var (
+	ErrParse          = &RPCError{Code: ParseErrorCode}
+	ErrInvalidRequest = &RPCError{Code: InvalidRequestCode}
+	ErrMethodNotFound = &RPCError{Code: MethodNotFoundCode}
+	ErrInvalidParams  = &RPCError{Code: InvalidParamsCode}
+	ErrInternal       = &RPCError{Code: InternalErrorCode}
+)
+
+func (e *RPCError) Error() string {
+	if e.Message == "" {
+		return fmt.Sprintf("rpc error %d", e.Code)
+	}
+
+	return e.Message
+}
+
+// Is reports whether target is an *RPCError with the same code.
+// If target has a message, it must also be found in the message of e (case insensitive).
+// This lets sentinels like daemon.ErrBlockNotFound match one kind of internal error.
+func (e *RPCError) Is(target error) bool {
+	t, ok := target.(*RPCError)
+	if !ok || e.Code != t.Code {
+		return false
+	}
+
+	return t.Message == "" || strings.Contains(strings.ToLower(e.Message), strings.ToLower(t.Message))
+}
+
+// Convert an error returned by the jrpc2 client to *RPCError so the RPC and WebSocket clients return the same types.
+// Other errors (transport, context, decoding) are returned as is.
+func FromJRPC2Error(err error) error {
+	var jrpcErr *jrpc2.Error
+	if errors.As(err, &jrpcErr) {
+		return &RPCError{
+			Code:    int(jrpcErr.Code),
+			Message: jrpcErr.Message,
+			Data:    jrpcErr.Data,
+		}
+	}
+
+	return err
+}
@@ -0,0 +1,54 @@
+package rpc
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/creachadair/jrpc2"
+)
+
+func TestRPCErrorIs(t *testing.T) {
+	err := fmt.Errorf("get_block: %w", &RPCError{Code: InternalErrorCode, Message: "Block not found at topoheight 5"})
+
+	if !errors.Is(err, ErrInternal) {
+		t.Error("Expected internal error")
+	}
+
+	if errors.Is(err, ErrInvalidParams) {
+		t.Error("Expected code mismatch")
+	}
+
+	if !errors.Is(err, &RPCError{Code: InternalErrorCode, Message: "block not found"}) {
+		t.Error("Expected message match")
+	}
+
+	if errors.Is(err, &RPCError{Code: InternalErrorCode, Message: "account not found"}) {
+		t.Error("Expected message mismatch")
+	}
+
+	var rpcErr *RPCError
+	if !errors.As(err, &rpcErr) || rpcErr.Code != InternalErrorCode {
+		t.Errorf("Expected *RPCError, got %v", err)
+	}
+}
+
+func TestFromJRPC2Error(t *testing.T) {
+	err := FromJRPC2Error(&jrpc2.Error{Code: InvalidParamsCode, Message: "Invalid params: missing field"})
+	if !errors.Is(err, ErrInvalidParams) {
+		t.Errorf("Expected invalid params, got %v", err)
+	}
+
+	if err.Error() != "Invalid params: missing field" {
+		t.Errorf("Unexpected message %s", err)
+	}
+
+	other := errors.New("connection refused")
+	if FromJRPC2Error(other) != other {
+		t.Error("Expected other errors to be returned as is")
+	}
+
+	if FromJRPC2Error(nil) != nil {
+		t.Error("Expected nil")
+	}
+}
@@ -0,0 +1,341 @@
+// Package rpctest provides a local JSON-RPC server speaking the same HTTP and WebSocket
+// protocol as the XELIS daemon and wallet. It is used by daemontest and wallettest.
+package rpctest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+
+	"github.com/gorilla/websocket"
+	"github.com/xelis-project/xelis-go-sdk/rpc"
+)
+
+type Handler func(params json.RawMessage) (result interface{}, err *rpc.RPCError)
+
+func NewError(code int, format string, a ...interface{}) *rpc.RPCError {
+	return &rpc.RPCError{Code: code, Message: fmt.Sprintf(format, a...)}
+}
+
+func InvalidParams(err error) *rpc.RPCError {
+	return NewError(rpc.InvalidParamsCode, "Invalid params: %s", err)
+}
+
+// Decode the request params into v. Missing params are treated as an empty object.
+func ParseParams(params json.RawMessage, v interface{}) *rpc.RPCError {
+	if len(params) == 0 || string(params) == "null" {
+		params = json.RawMessage(`{}`)
+	}
+
+	err := json.Unmarshal(params, v)
+	if err != nil {
+		return InvalidParams(err)
+	}
+
+	return nil
+}
+
+type request struct {
+	ID      *int64          `json:"id"`
+	JSONRPC string          `json:"jsonrpc"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params"`
+}
+
+type response struct {
+	ID      *int64        `json:"id"`
+	JSONRPC string        `json:"jsonrpc"`
+	Result  interface{}   `json:"result,omitempty"`
+	Error   *rpc.RPCError `json:"error,omitempty"`
+}
+
+type client struct {
+	conn   *websocket.Conn
+	mutex  sync.Mutex
+	events map[string]int64
+}
+
+func (c *client) write(v interface{}) error {
+	defer c.mutex.Unlock()
+	c.mutex.Lock()
+	return c.conn.WriteJSON(v)
+}
+
+type Server struct {
+	// Base url of the server. Clients must connect to URL + "/json_rpc".
+	URL string
+	// Called for every HTTP request and WebSocket upgrade. Return false to answer 401 Unauthorized.
+	Authorize func(r *http.Request) bool
+
+	server   *httptest.Server
+	handlers map[string]Handler
+	clients  map[*client]bool
+	mutex    sync.Mutex
+	upgrader websocket.Upgrader
+}
+
+// Starts a server listening on a random local port. Call Close when done.
+func NewServer() *Server {
+	s := &Server{
+		handlers: make(map[string]Handler),
+		clients:  make(map[*client]bool),
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/json_rpc", s.serve)
+	s.server = httptest.NewServer(mux)
+	s.URL = s.server.URL
+	return s
+}
+
+// Endpoint to use with NewRPC.
+func (s *Server) RPCEndpoint() string {
+	return s.URL + "/json_rpc"
+}
+
+// Endpoint to use with NewWebSocket.
+func (s *Server) WSEndpoint() string {
+	return "ws" + strings.TrimPrefix(s.URL, "http") + "/json_rpc"
+}
+
+func (s *Server) Close() {
+	s.mutex.Lock()
+	for c := range s.clients {
+		c.conn.Close()
+	}
+	s.mutex.Unlock()
+
+	s.server.Close()
+}
+
+// Drop every WebSocket connection without stopping the server.
+func (s *Server) CloseClientConnections() {
+	defer s.mutex.Unlock()
+	s.mutex.Lock()
+
+	for c := range s.clients {
+		c.conn.Close()
+	}
+}
+
+func (s *Server) Register(method string, handler Handler) {
+	defer s.mutex.Unlock()
+	s.mutex.Lock()
+	s.handlers[method] = handler
+}
+
+// Send the event to every WebSocket client subscribed to it.
+// Like the daemon, the event name is added to the result if it is a JSON object.
+func (s *Server) Notify(event string, value interface{}) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+
+	var fields map[string]json.RawMessage
+	if json.Unmarshal(data, &fields) == nil && fields != nil {
+		fields["event"], _ = json.Marshal(event)
+		data, err = json.Marshal(fields)
+		if err != nil {
+			return err
+		}
+	}
+
+	s.mutex.Lock()
+	var clients []*client
+	for c := range s.clients {
+		clients = append(clients, c)
+	}
+	s.mutex.Unlock()
+
+	for _, c := range clients {
+		c.mutex.Lock()
+		id, ok := c.events[event]
+		if ok {
+			c.conn.WriteJSON(response{ID: &id, JSONRPC: "2.0", Result: json.RawMessage(data)})
+		}
+		c.mutex.Unlock()
+	}
+
+	return nil
+}
+
+func (s *Server) serve(w http.ResponseWriter, r *http.Request) {
+	if s.Authorize != nil && !s.Authorize(r) {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	if websocket.IsWebSocketUpgrade(r) {
+		s.serveWebSocket(w, r)
+		return
+	}
+
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var body json.RawMessage
+	err := json.NewDecoder(r.Body).Decode(&body)
+	if err != nil {
+		writeJSON(w, response{JSONRPC: "2.0", Error: NewError(rpc.ParseErrorCode, "Parse error: %s", err)})
+		return
+	}
+
+	trimmed := strings.TrimSpace(string(body))
+	if strings.HasPrefix(trimmed, "[") {
+		var batch []json.RawMessage
+		err = json.Unmarshal(body, &batch)
+		if err != nil || len(batch) == 0 {
+			writeJSON(w, response{JSONRPC: "2.0", Error: NewError(rpc.InvalidRequestCode, "Invalid batch request")})
+			return
+		}
+
+		var results []response
+		for _, item := range batch {
+			res, ok := s.handle(item)
+			if ok {
+				results = append(results, res)
+			}
+		}
+
+		if len(results) == 0 {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+
+		writeJSON(w, results)
+		return
+	}
+
+	res, ok := s.handle(body)
+	if !ok {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	writeJSON(w, res)
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(v)
+}
+
+// Returns false if the request is a notification and doesn't need a response.
+func (s *Server) handle(data []byte) (res response, ok bool) {
+	res.JSONRPC = "2.0"
+
+	var req request
+	err := json.Unmarshal(data, &req)
+	if err != nil {
+		res.Error = NewError(rpc.ParseErrorCode, "Parse error: %s", err)
+		return res, true
+	}
+
+	res.ID = req.ID
+	s.mutex.Lock()
+	handler, found := s.handlers[req.Method]
+	s.mutex.Unlock()
+
+	if !found {
+		res.Error = NewError(rpc.MethodNotFoundCode, "Method '%s' in request was not found", req.Method)
+		return res, req.ID != nil
+	}
+
+	result, rpcErr := handler(req.Params)
+	if rpcErr != nil {
+		res.Error = rpcErr
+	} else {
+		res.Result = result
+		if result == nil {
+			res.Result = json.RawMessage(`null`)
+		}
+	}
+
+	return res, req.ID != nil
+}
+
+func (s *Server) serveWebSocket(w http.ResponseWriter, r *http.Request) {
+	conn, err := s.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		return
+	}
+
+	c := &client{conn: conn, events: make(map[string]int64)}
+	s.mutex.Lock()
+	s.clients[c] = true
+	s.mutex.Unlock()
+
+	defer func() {
+		s.mutex.Lock()
+		delete(s.clients, c)
+		s.mutex.Unlock()
+		conn.Close()
+	}()
+
+	for {
+		_, msg, err := conn.ReadMessage()
+		if err != nil {
+			return
+		}
+
+		var req request
+		err = json.Unmarshal(msg, &req)
+		if err == nil && (req.Method == "subscribe" || req.Method == "unsubscribe") {
+			c.write(s.handleSubscription(c, req))
+			continue
+		}
+
+		res, ok := s.handle(msg)
+		if ok {
+			c.write(res)
+		}
+	}
+}
+
+func (s *Server) handleSubscription(c *client, req request) response {
+	res := response{ID: req.ID, JSONRPC: "2.0"}
+
+	var params struct {
+		Notify string `json:"notify"`
+	}
+
+	rpcErr := ParseParams(req.Params, &params)
+	if rpcErr != nil {
+		res.Error = rpcErr
+		return res
+	}
+
+	if req.ID == nil {
+		res.Error = NewError(rpc.InvalidRequestCode, "Subscription requires an id")
+		return res
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	_, subscribed := c.events[params.Notify]
+	if req.Method == "subscribe" {
+		if subscribed {
+			res.Error = NewError(rpc.InternalErrorCode, "Event is already subscribed")
+			return res
+		}
+
+		c.events[params.Notify] = *req.ID
+	} else {
+		if !subscribed {
+			res.Error = NewError(rpc.InternalErrorCode, "Event is not subscribed")
+			return res
+		}
+
+		delete(c.events, params.Notify)
+	}
+
+	res.Result = true
+	return res
+}
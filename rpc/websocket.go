@@ -1,8 +1,9 @@
 package rpc
 
 import (
+	"context"
 	"encoding/json"
-	"fmt"
+	"errors"
 	"net/http"
 	"net/url"
 	"sync"
@@ -11,17 +12,62 @@ import (
 	"github.com/gorilla/websocket"
 )
 
+var ErrTimeout = errors.New("timeout waiting for response")
+var ErrConnectionClosed = errors.New("connection closed before receiving a response")
+
+type ReconnectOptions struct {
+	// Delay before the first reconnect attempt. It doubles after every failed attempt.
+	MinBackoff time.Duration
+	// Maximum delay between two reconnect attempts.
+	MaxBackoff time.Duration
+	// Called once the connection is restored and every event has been subscribed again.
+	OnReconnect func()
+}
+
+var DefaultReconnectOptions = ReconnectOptions{
+	MinBackoff: 1 * time.Second,
+	MaxBackoff: 30 * time.Second,
+}
+
 type WebSocket struct {
-	CallTimeout   time.Duration
-	id            int64
-	conn          *websocket.Conn
-	channels      map[int64]chan RPCResponse
-	events        map[string]int64
-	mutex         sync.Mutex
+	CallTimeout time.Duration
+	id          int64
+	conn        *websocket.Conn
+	channels    map[int64]chan RPCResponse
+	events      map[string]int64
+	listeners   map[int64][]*listener
+	mutex       sync.Mutex
+	// Held while subscribing or unsubscribing, so an event is never subscribed twice.
+	eventsMutex   sync.Mutex
 	ConnectionErr chan error
+
+	endpoint     string
+	header       http.Header
+	reconnect    *ReconnectOptions
+	resubscribes map[int64]chan RPCResponse
+	closed       chan struct{}
 }
 
 func NewWebSocket(endpoint string, header http.Header) (*WebSocket, error) {
+	return newWebSocket(endpoint, header, nil)
+}
+
+// Same as NewWebSocket but the connection is dialed again with backoff when it drops.
+// Every event listened with ListenEventFunc is subscribed again after reconnecting.
+// Errors are still pushed to ConnectionErr, but only if someone is listening.
+func NewWebSocketWithReconnect(endpoint string, header http.Header, options ReconnectOptions) (*WebSocket, error) {
+	if options.MinBackoff <= 0 {
+		options.MinBackoff = DefaultReconnectOptions.MinBackoff
+	}
+
+	if options.MaxBackoff < options.MinBackoff {
+		options.MaxBackoff = options.MinBackoff
+	}
+
+	return newWebSocket(endpoint, header, &options)
+}
+
+func newWebSocket(endpoint string, header http.Header, reconnect *ReconnectOptions) (*WebSocket, error) {
 	socketUrl, err := url.Parse(endpoint)
 	if err != nil {
 		return nil, err
@@ -37,7 +83,13 @@ func NewWebSocket(endpoint string, header http.Header) (*WebSocket, error) {
 		conn:          conn,
 		channels:      make(map[int64]chan RPCResponse),
 		events:        make(map[string]int64),
+		listeners:     make(map[int64][]*listener),
 		ConnectionErr: make(chan error),
+		endpoint:      socketUrl.String(),
+		header:        header,
+		reconnect:     reconnect,
+		resubscribes:  make(map[int64]chan RPCResponse),
+		closed:        make(chan struct{}),
 	}
 
 	go ws.listen()
@@ -45,41 +97,230 @@ func NewWebSocket(endpoint string, header http.Header) (*WebSocket, error) {
 }
 
 func (w *WebSocket) listen() {
-	go func() {
-		for {
-			_, msg, err := w.conn.ReadMessage()
-			if err != nil {
+	for {
+		w.mutex.Lock()
+		conn := w.conn
+		w.mutex.Unlock()
+
+		_, msg, err := conn.ReadMessage()
+		if err != nil {
+			if w.reconnect == nil {
 				w.ConnectionErr <- err
 				return
 			}
 
-			w.mutex.Lock()
-			var rpcResponse RPCResponse
-			json.Unmarshal(msg, &rpcResponse)
-			id := rpcResponse.ID
-			ch, ok := w.channels[rpcResponse.ID]
-			if ok {
-				ch <- rpcResponse
-
-				// Close channel if it's not an event.
-				// We will never receive data from that channel ever again, because the id is incremented each call.
-				// I'm not sure if it's OK to leave a channel open. Maybe it's picked up by GC, but I prefer to close manually and avoid leak.
-				isEvent := false
-				for _, eventId := range w.events {
-					if eventId == id {
-						isEvent = true
-						break
-					}
-				}
-
-				if !isEvent {
-					close(ch)
-					delete(w.channels, id)
-				}
+			if w.isClosed() {
+				return
+			}
+
+			w.notifyErr(err)
+			if !w.redial() {
+				return
+			}
+
+			continue
+		}
+
+		w.handleMessage(msg)
+	}
+}
+
+// Nothing is sent while holding the mutex. Responses go to buffered channels and events to the queue of each listener,
+// so the reader never waits on a listener, even one making a call from its callback.
+func (w *WebSocket) handleMessage(msg []byte) {
+	var rpcResponse RPCResponse
+	json.Unmarshal(msg, &rpcResponse)
+	id := rpcResponse.ID
+
+	w.mutex.Lock()
+	// Response of a subscribe sent after reconnecting. It must not reach the event listeners.
+	ch, ok := w.resubscribes[id]
+	if ok {
+		delete(w.resubscribes, id)
+	} else {
+		ch, ok = w.channels[id]
+		if ok {
+			// We will never receive data from that channel ever again, because the id is incremented each call.
+			delete(w.channels, id)
+		}
+	}
+
+	listeners := append([]*listener{}, w.listeners[id]...)
+	w.mutex.Unlock()
+
+	if ok {
+		ch <- rpcResponse
+		close(ch)
+		return
+	}
+
+	for _, l := range listeners {
+		l.push(rpcResponse)
+	}
+}
+
+// Calls onData for every event in its own goroutine, in the order they were received.
+type listener struct {
+	onData func(RPCResponse)
+	mutex  sync.Mutex
+	queue  []RPCResponse
+	wake   chan struct{}
+	closed chan struct{}
+}
+
+func newListener(onData func(RPCResponse)) *listener {
+	l := &listener{
+		onData: onData,
+		wake:   make(chan struct{}, 1),
+		closed: make(chan struct{}),
+	}
+
+	go l.run()
+	return l
+}
+
+func (l *listener) push(res RPCResponse) {
+	l.mutex.Lock()
+	l.queue = append(l.queue, res)
+	l.mutex.Unlock()
+
+	select {
+	case l.wake <- struct{}{}:
+	default:
+	}
+}
+
+func (l *listener) run() {
+	for {
+		select {
+		case <-l.closed:
+			return
+		case <-l.wake:
+		}
+
+		l.mutex.Lock()
+		queue := l.queue
+		l.queue = nil
+		l.mutex.Unlock()
+
+		for _, res := range queue {
+			select {
+			case <-l.closed:
+				return
+			default:
+			}
+
+			l.onData(res)
+		}
+	}
+}
+
+// Must be called once, with the mutex of the WebSocket locked.
+func (l *listener) close() {
+	close(l.closed)
+}
+
+func (w *WebSocket) isClosed() bool {
+	select {
+	case <-w.closed:
+		return true
+	default:
+		return false
+	}
+}
+
+// Don't block the reader if nobody is listening to ConnectionErr.
+func (w *WebSocket) notifyErr(err error) {
+	select {
+	case w.ConnectionErr <- err:
+	default:
+	}
+}
+
+func (w *WebSocket) redial() bool {
+	w.mutex.Lock()
+	// Pending calls will never get their response on a new connection.
+	for id, ch := range w.channels {
+		close(ch)
+		delete(w.channels, id)
+	}
+
+	for id, ch := range w.resubscribes {
+		close(ch)
+		delete(w.resubscribes, id)
+	}
+	w.mutex.Unlock()
+
+	backoff := w.reconnect.MinBackoff
+	for {
+		select {
+		case <-w.closed:
+			return false
+		case <-time.After(backoff):
+		}
+
+		conn, _, err := websocket.DefaultDialer.Dial(w.endpoint, w.header)
+		if err != nil {
+			w.notifyErr(err)
+
+			backoff *= 2
+			if backoff > w.reconnect.MaxBackoff {
+				backoff = w.reconnect.MaxBackoff
 			}
+
+			continue
+		}
+
+		w.mutex.Lock()
+		if w.isClosed() {
 			w.mutex.Unlock()
+			conn.Close()
+			return false
 		}
-	}()
+
+		w.conn = conn
+		w.mutex.Unlock()
+
+		go w.resubscribe()
+		return true
+	}
+}
+
+func (w *WebSocket) resubscribe() {
+	w.mutex.Lock()
+	events := make(map[string]int64, len(w.events))
+	for event, id := range w.events {
+		events[event] = id
+	}
+	w.mutex.Unlock()
+
+	for event, id := range events {
+		// Reuse the subscription id so the event channel doesn't have to move.
+		// Ids are never reused by Call() so there is no conflict on the new connection.
+		rpcRequest := RPCRequest{ID: id, JSONRPC: "2.0", Method: "subscribe", Params: map[string]interface{}{
+			"notify": event,
+		}}
+
+		data, err := json.Marshal(rpcRequest)
+		if err != nil {
+			w.notifyErr(err)
+			continue
+		}
+
+		res, err := w.call(context.Background(), w.resubscribes, id, data)
+		if err != nil {
+			w.notifyErr(err)
+			continue
+		}
+
+		if res.Error != nil {
+			w.notifyErr(res.Error)
+		}
+	}
+
+	if w.reconnect.OnReconnect != nil {
+		w.reconnect.OnReconnect()
+	}
 }
 
 func (w *WebSocket) subscribeEvent(event string) (RPCResponse, error) {
@@ -98,6 +339,10 @@ func (w *WebSocket) Close() error {
 	defer w.mutex.Unlock()
 	w.mutex.Lock()
 
+	if !w.isClosed() {
+		close(w.closed)
+	}
+
 	// Remove channels and events.
 	// We don't need to send unsubscribe event if we just close the connection.
 	for id := range w.channels {
@@ -106,6 +351,19 @@ func (w *WebSocket) Close() error {
 		delete(w.channels, id)
 	}
 
+	for id := range w.resubscribes {
+		ch := w.resubscribes[id]
+		close(ch)
+		delete(w.resubscribes, id)
+	}
+
+	for id, listeners := range w.listeners {
+		for _, l := range listeners {
+			l.close()
+		}
+		delete(w.listeners, id)
+	}
+
 	for event := range w.events {
 		delete(w.events, event)
 	}
@@ -113,8 +371,16 @@ func (w *WebSocket) Close() error {
 	return w.conn.Close()
 }
 
+// Unsubscribe the event and remove all its listeners, including the ones added by someone else on this connection.
+// Use EventListener.Close to only remove one listener.
 func (w *WebSocket) CloseEvent(event string) error {
+	defer w.eventsMutex.Unlock()
+	w.eventsMutex.Lock()
+
+	w.mutex.Lock()
 	id, ok := w.events[event]
+	w.mutex.Unlock()
+
 	if ok {
 		res, err := w.unsubscribeEvent(event)
 		if err != nil {
@@ -122,13 +388,14 @@ func (w *WebSocket) CloseEvent(event string) error {
 		}
 
 		if res.Error != nil {
-			return fmt.Errorf(res.Error.Message)
+			return res.Error
 		}
 
 		w.mutex.Lock()
-		ch := w.channels[id]
-		close(ch)
-		delete(w.channels, id)
+		for _, l := range w.listeners[id] {
+			l.close()
+		}
+		delete(w.listeners, id)
 		delete(w.events, event)
 		w.mutex.Unlock()
 	}
@@ -136,8 +403,30 @@ func (w *WebSocket) CloseEvent(event string) error {
 	return nil
 }
 
+// Every listener of an event receives all its notifications.
 func (w *WebSocket) ListenEventFunc(event string, onData func(RPCResponse)) (err error) {
+	_, err = w.AddEventListener(event, onData)
+	return
+}
+
+// A single listener of an event, removed with Close without touching the other listeners of the event.
+type EventListener struct {
+	ws       *WebSocket
+	event    string
+	id       int64
+	listener *listener
+}
+
+// Same as ListenEventFunc but the listener can be closed on its own.
+// The event is subscribed by the first listener and unsubscribed when the last one is closed.
+func (w *WebSocket) AddEventListener(event string, onData func(RPCResponse)) (eventListener *EventListener, err error) {
+	defer w.eventsMutex.Unlock()
+	w.eventsMutex.Lock()
+
+	w.mutex.Lock()
 	id, ok := w.events[event]
+	w.mutex.Unlock()
+
 	if !ok {
 		var res RPCResponse
 		res, err = w.subscribeEvent(event)
@@ -146,72 +435,151 @@ func (w *WebSocket) ListenEventFunc(event string, onData func(RPCResponse)) (err
 		}
 
 		if res.Error != nil {
-			err = fmt.Errorf(res.Error.Message)
+			err = res.Error
 			return
 		}
 
 		id = res.ID
+		w.mutex.Lock()
 		w.events[event] = id
+		w.mutex.Unlock()
 	}
 
-	ch, ok := w.channels[id]
-	if !ok {
-		ch = make(chan RPCResponse)
-		w.channels[id] = ch
-	}
+	eventListener = &EventListener{ws: w, event: event, id: id, listener: newListener(onData)}
+	w.mutex.Lock()
+	w.listeners[id] = append(w.listeners[id], eventListener.listener)
+	w.mutex.Unlock()
 
-	go func() {
-		for res := range ch {
-			onData(res)
+	return
+}
+
+// Closing a listener twice, or after CloseEvent, does nothing.
+func (e *EventListener) Close() error {
+	w := e.ws
+	defer w.eventsMutex.Unlock()
+	w.eventsMutex.Lock()
+
+	w.mutex.Lock()
+	listeners := w.listeners[e.id]
+	found := false
+	for i, l := range listeners {
+		if l == e.listener {
+			l.close()
+			listeners = append(listeners[:i:i], listeners[i+1:]...)
+			found = true
+			break
 		}
-	}()
+	}
 
-	return
+	last := found && len(listeners) == 0
+	if last {
+		delete(w.listeners, e.id)
+		delete(w.events, e.event)
+	} else if found {
+		w.listeners[e.id] = listeners
+	}
+	w.mutex.Unlock()
+
+	if !last {
+		return nil
+	}
+
+	res, err := w.unsubscribeEvent(e.event)
+	if err != nil {
+		return err
+	}
+
+	if res.Error != nil {
+		return res.Error
+	}
+
+	return nil
 }
 
 func (w *WebSocket) Call(method string, params interface{}) (res RPCResponse, err error) {
+	return w.CallContext(context.Background(), method, params)
+}
+
+// The call is abandoned when ctx is done. CallTimeout still applies if it expires first.
+func (w *WebSocket) CallContext(ctx context.Context, method string, params interface{}) (res RPCResponse, err error) {
+	w.mutex.Lock()
 	w.id++
-	rpcRequest := RPCRequest{ID: w.id, JSONRPC: "2.0", Method: method, Params: params}
+	id := w.id
+	w.mutex.Unlock()
+
+	rpcRequest := RPCRequest{ID: id, JSONRPC: "2.0", Method: method, Params: params}
 	data, err := json.Marshal(rpcRequest)
 	if err != nil {
 		return
 	}
 
-	return w.RawCall(w.id, data)
+	return w.RawCallContext(ctx, id, data)
 }
 
 func (w *WebSocket) RawCall(id int64, data []byte) (res RPCResponse, err error) {
-	ch := make(chan RPCResponse)
-	w.channels[id] = ch
+	return w.RawCallContext(context.Background(), id, data)
+}
 
-	var timer *time.Timer
-	if w.CallTimeout > 0 {
-		timer = time.AfterFunc(w.CallTimeout, func() {
-			defer w.mutex.Unlock()
-			w.mutex.Lock()
-
-			ch, ok := w.channels[w.id]
-			if ok {
-				close(ch)
-				delete(w.channels, w.id)
-				err = fmt.Errorf("timeout waiting for response")
-			}
-		})
+func (w *WebSocket) RawCallContext(ctx context.Context, id int64, data []byte) (res RPCResponse, err error) {
+	return w.call(ctx, w.channels, id, data)
+}
+
+func (w *WebSocket) call(ctx context.Context, pending map[int64]chan RPCResponse, id int64, data []byte) (res RPCResponse, err error) {
+	err = ctx.Err()
+	if err != nil {
+		return
 	}
 
+	// Buffered so the reader never blocks on a call that already timed out.
+	ch := make(chan RPCResponse, 1)
+
+	w.mutex.Lock()
+	pending[id] = ch
 	err = w.conn.WriteMessage(websocket.TextMessage, data)
+	if err != nil {
+		delete(pending, id)
+	}
+	w.mutex.Unlock()
+
 	if err != nil {
 		return
 	}
 
-	res = <-ch
-	if timer != nil {
-		timer.Stop()
+	var timeout <-chan time.Time
+	if w.CallTimeout > 0 {
+		timer := time.NewTimer(w.CallTimeout)
+		defer timer.Stop()
+		timeout = timer.C
+	}
+
+	select {
+	case response, ok := <-ch:
+		if !ok {
+			err = ErrConnectionClosed
+			return
+		}
+
+		res = response
+	case <-timeout:
+		w.removePending(pending, id, ch)
+		err = ErrTimeout
+	case <-ctx.Done():
+		w.removePending(pending, id, ch)
+		err = ctx.Err()
 	}
 
 	return
 }
 
+func (w *WebSocket) removePending(pending map[int64]chan RPCResponse, id int64, ch chan RPCResponse) {
+	defer w.mutex.Unlock()
+	w.mutex.Lock()
+
+	if pending[id] == ch {
+		delete(pending, id)
+	}
+}
+
 func JsonFormatResponse(res RPCResponse, resErr error, result any) (err error) {
 	if resErr != nil {
 		err = resErr
@@ -219,7 +587,7 @@ func JsonFormatResponse(res RPCResponse, resErr error, result any) (err error) {
 	}
 
 	if res.Error != nil {
-		err = fmt.Errorf(res.Error.Message)
+		err = res.Error
 		return
 	}
 
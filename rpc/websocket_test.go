@@ -0,0 +1,297 @@
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// Small server answering subscribe calls and sending one event right after each subscription.
+// The connection is dropped after the first event to force a reconnect.
+func useDroppingServer(t *testing.T) (server *httptest.Server, subscribes chan int64) {
+	subscribes = make(chan int64, 10)
+	var mutex sync.Mutex
+	connections := 0
+
+	upgrader := websocket.Upgrader{}
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		mutex.Lock()
+		connections++
+		drop := connections == 1
+		mutex.Unlock()
+
+		for {
+			var req RPCRequest
+			err := conn.ReadJSON(&req)
+			if err != nil {
+				return
+			}
+
+			conn.WriteJSON(RPCResponse{ID: req.ID, Result: json.RawMessage(`true`)})
+
+			if req.Method == "subscribe" {
+				subscribes <- req.ID
+				conn.WriteJSON(RPCResponse{ID: req.ID, Result: json.RawMessage(`{"height":1}`)})
+
+				if drop {
+					return
+				}
+			}
+		}
+	}))
+
+	return
+}
+
+func TestWSReconnectResubscribe(t *testing.T) {
+	server, subscribes := useDroppingServer(t)
+	defer server.Close()
+
+	reconnected := make(chan bool, 1)
+	endpoint := "ws" + strings.TrimPrefix(server.URL, "http")
+	ws, err := NewWebSocketWithReconnect(endpoint, nil, ReconnectOptions{
+		MinBackoff: 10 * time.Millisecond,
+		OnReconnect: func() {
+			reconnected <- true
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ws.Close()
+
+	events := make(chan RPCResponse, 10)
+	err = ws.ListenEventFunc("new_block", func(res RPCResponse) {
+		events <- res
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	firstId := <-subscribes
+
+	select {
+	case <-reconnected:
+	case <-time.After(5 * time.Second):
+		t.Fatal("connection was not restored")
+	}
+
+	secondId := <-subscribes
+	if firstId != secondId {
+		t.Errorf("Expected subscription id %d, got %d", firstId, secondId)
+	}
+
+	select {
+	case res := <-events:
+		if string(res.Result) != `{"height":1}` {
+			t.Errorf("Expected event data, got %s", res.Result)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("event was not received after reconnecting")
+	}
+
+	res, err := ws.Call("get_info", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Logf("%+v", res)
+}
+
+func TestWSCallContextCancel(t *testing.T) {
+	upgrader := websocket.Upgrader{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		// never answer
+		for {
+			_, _, err := conn.ReadMessage()
+			if err != nil {
+				return
+			}
+		}
+	}))
+	defer server.Close()
+
+	ws, err := NewWebSocket("ws"+strings.TrimPrefix(server.URL, "http"), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ws.Close()
+
+	ws.CallTimeout = 0
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	_, err = ws.CallContext(ctx, "get_info", nil)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Expected deadline exceeded, got %v", err)
+	}
+}
+
+// Server answering every call. The "start" method sends count events to the last subscription.
+func useEventServer(t *testing.T, count int) (server *httptest.Server, methods chan string) {
+	methods = make(chan string, 100)
+	upgrader := websocket.Upgrader{}
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		var mutex sync.Mutex
+		var subscription int64
+		for {
+			var req RPCRequest
+			err := conn.ReadJSON(&req)
+			if err != nil {
+				return
+			}
+
+			methods <- req.Method
+			mutex.Lock()
+			conn.WriteJSON(RPCResponse{ID: req.ID, Result: json.RawMessage(`true`)})
+			mutex.Unlock()
+
+			if req.Method == "subscribe" {
+				subscription = req.ID
+			}
+
+			if req.Method == "start" {
+				go func(id int64) {
+					for i := 0; i < count; i++ {
+						mutex.Lock()
+						err := conn.WriteJSON(RPCResponse{ID: id, Result: json.RawMessage(`{"height":1}`)})
+						mutex.Unlock()
+						if err != nil {
+							return
+						}
+					}
+				}(subscription)
+			}
+		}
+	}))
+
+	return
+}
+
+// A listener calling the server from its callback must not block the reader, even when more events keep coming.
+func TestWSCallFromListener(t *testing.T) {
+	server, _ := useEventServer(t, 20)
+	defer server.Close()
+
+	ws, err := NewWebSocket("ws"+strings.TrimPrefix(server.URL, "http"), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ws.Close()
+
+	done := make(chan error, 40)
+	for i := 0; i < 2; i++ {
+		err = ws.ListenEventFunc("new_block", func(res RPCResponse) {
+			_, err := ws.Call("get_info", nil)
+			done <- err
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	_, err = ws.Call("start", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// both listeners receive every event
+	for i := 0; i < 40; i++ {
+		select {
+		case err := <-done:
+			if err != nil {
+				t.Fatal(err)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("only %d calls answered", i)
+		}
+	}
+}
+
+func TestWSEventListenerClose(t *testing.T) {
+	server, methods := useEventServer(t, 5)
+	defer server.Close()
+
+	ws, err := NewWebSocket("ws"+strings.TrimPrefix(server.URL, "http"), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ws.Close()
+
+	closed, err := ws.AddEventListener("new_block", func(res RPCResponse) {
+		t.Error("Closed listener received an event")
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	events := make(chan RPCResponse, 10)
+	listener, err := ws.AddEventListener("new_block", func(res RPCResponse) {
+		events <- res
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = closed.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = ws.Call("start", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 5; i++ {
+		select {
+		case <-events:
+		case <-time.After(5 * time.Second):
+			t.Fatalf("only %d events received", i)
+		}
+	}
+
+	// the event is only unsubscribed with its last listener
+	err = listener.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{"subscribe", "start", "unsubscribe"}
+	for _, method := range expected {
+		if received := <-methods; received != method {
+			t.Fatalf("Expected %s, got %s", method, received)
+		}
+	}
+
+	err = listener.Close()
+	if err != nil || len(methods) != 0 {
+		t.Errorf("Expected closing twice to do nothing, got %v", err)
+	}
+}
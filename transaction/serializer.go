@@ -0,0 +1,157 @@
+package transaction
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+)
+
+var ErrTrailingBytes = errors.New("unexpected bytes after the transaction")
+var ErrTransfersAndBurn = errors.New("transaction data cannot have both transfers and burn")
+
+func ErrInvalidTag(name string, tag byte) error {
+	return fmt.Errorf("invalid %s tag %d", name, tag)
+}
+
+type Reader struct {
+	Reader *bytes.Reader
+}
+
+func (r *Reader) readU8() (value uint8, err error) {
+	value, err = r.Reader.ReadByte()
+	if err == io.EOF {
+		err = io.ErrUnexpectedEOF
+	}
+
+	return
+}
+
+func (r *Reader) readBool() (value bool, err error) {
+	data, err := r.readU8()
+	if err != nil {
+		return
+	}
+
+	switch data {
+	case 0:
+		value = false
+	case 1:
+		value = true
+	default:
+		err = ErrInvalidTag("bool", data)
+	}
+
+	return
+}
+
+func (r *Reader) readBytes(size int) (value []byte, err error) {
+	if size > r.Reader.Len() {
+		err = io.ErrUnexpectedEOF
+		return
+	}
+
+	value = make([]byte, size)
+	_, err = io.ReadFull(r.Reader, value)
+	return
+}
+
+func (r *Reader) readU16() (value uint16, err error) {
+	data, err := r.readBytes(2)
+	if err != nil {
+		return
+	}
+
+	value = binary.BigEndian.Uint16(data)
+	return
+}
+
+func (r *Reader) readU64() (value uint64, err error) {
+	data, err := r.readBytes(8)
+	if err != nil {
+		return
+	}
+
+	value = binary.BigEndian.Uint64(data)
+	return
+}
+
+func (r *Reader) readFixed(value []byte) (err error) {
+	data, err := r.readBytes(len(value))
+	if err != nil {
+		return
+	}
+
+	copy(value, data)
+	return
+}
+
+// Vec<u8> prefixed by its u16 size.
+func (r *Reader) readVec() (value []byte, err error) {
+	size, err := r.readU16()
+	if err != nil {
+		return
+	}
+
+	value, err = r.readBytes(int(size))
+	return
+}
+
+type Writer struct {
+	Writer io.Writer
+}
+
+func (w *Writer) writeU8(value uint8) (err error) {
+	_, err = w.Writer.Write([]byte{value})
+	return
+}
+
+func (w *Writer) writeBool(value bool) (err error) {
+	if value {
+		return w.writeU8(1)
+	}
+
+	return w.writeU8(0)
+}
+
+func (w *Writer) writeBytes(value []byte) (err error) {
+	_, err = w.Writer.Write(value)
+	return
+}
+
+func (w *Writer) writeU16(value uint16) (err error) {
+	data := make([]byte, 2)
+	binary.BigEndian.PutUint16(data, value)
+	return w.writeBytes(data)
+}
+
+func (w *Writer) writeU64(value uint64) (err error) {
+	data := make([]byte, 8)
+	binary.BigEndian.PutUint64(data, value)
+	return w.writeBytes(data)
+}
+
+func (w *Writer) writeVec(value []byte) (err error) {
+	if len(value) > 0xFFFF {
+		err = fmt.Errorf("size %d is above the max limit of 65535 bytes", len(value))
+		return
+	}
+
+	err = w.writeU16(uint16(len(value)))
+	if err != nil {
+		return
+	}
+
+	return w.writeBytes(value)
+}
+
+// Count of items prefixed as a single byte.
+func (w *Writer) writeCount(name string, count int) (err error) {
+	if count > 0xFF {
+		err = fmt.Errorf("%d %s is above the max limit of 255", count, name)
+		return
+	}
+
+	return w.writeU8(uint8(count))
+}
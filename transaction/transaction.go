@@ -0,0 +1,438 @@
+package transaction
+
+import (
+	"bytes"
+	"encoding/hex"
+)
+
+type Hash [32]byte
+
+// Compressed Ristretto point, used for public keys, commitments and decrypt handles.
+type CompressedPoint [32]byte
+
+type Scalar [32]byte
+
+type Signature [64]byte
+
+const (
+	BurnTag      byte = 0
+	TransfersTag byte = 1
+)
+
+// Ciphertext validity proof of a transfer.
+type Proof struct {
+	Y_0 CompressedPoint
+	Y_1 CompressedPoint
+	Z_R Scalar
+	Z_X Scalar
+}
+
+type EqProof struct {
+	Y_0 CompressedPoint
+	Y_1 CompressedPoint
+	Y_2 CompressedPoint
+	Z_R Scalar
+	Z_S Scalar
+	Z_X Scalar
+}
+
+type Transfer struct {
+	Asset           Hash
+	Destination     CompressedPoint
+	ExtraData       *[]byte
+	Commitment      CompressedPoint
+	SenderHandle    CompressedPoint
+	ReceiverHandle  CompressedPoint
+	CTValidityProof Proof
+}
+
+type Burn struct {
+	Asset  Hash
+	Amount uint64
+}
+
+// Either Transfers or Burn is set.
+type TransactionData struct {
+	Transfers []Transfer
+	Burn      *Burn
+}
+
+type Reference struct {
+	Hash       Hash
+	Topoheight uint64
+}
+
+type SourceCommitment struct {
+	Commitment CompressedPoint
+	Proof      EqProof
+	Asset      Hash
+}
+
+type Transaction struct {
+	Version           uint8
+	Source            CompressedPoint
+	Data              TransactionData
+	Fee               uint64
+	Nonce             uint64
+	SourceCommitments []SourceCommitment
+	RangeProof        []byte
+	Reference         Reference
+	Signature         Signature
+}
+
+// Decode a transaction from its binary format, like the tx_as_hex of build_transaction once hex decoded.
+func Decode(data []byte) (tx Transaction, err error) {
+	reader := Reader{Reader: bytes.NewReader(data)}
+	tx, err = reader.ReadTransaction()
+	if err != nil {
+		return
+	}
+
+	if reader.Reader.Len() > 0 {
+		err = ErrTrailingBytes
+	}
+
+	return
+}
+
+func DecodeHex(value string) (tx Transaction, err error) {
+	data, err := hex.DecodeString(value)
+	if err != nil {
+		return
+	}
+
+	return Decode(data)
+}
+
+// Binary format of the transaction, as sent to the daemon with submit_transaction.
+func (tx *Transaction) Encode() (data []byte, err error) {
+	buf := new(bytes.Buffer)
+	writer := Writer{Writer: buf}
+	err = writer.WriteTransaction(*tx)
+	if err != nil {
+		return
+	}
+
+	data = buf.Bytes()
+	return
+}
+
+func (tx *Transaction) EncodeHex() (value string, err error) {
+	data, err := tx.Encode()
+	if err != nil {
+		return
+	}
+
+	value = hex.EncodeToString(data)
+	return
+}
+
+func (r *Reader) ReadTransaction() (tx Transaction, err error) {
+	tx.Version, err = r.readU8()
+	if err != nil {
+		return
+	}
+
+	err = r.readFixed(tx.Source[:])
+	if err != nil {
+		return
+	}
+
+	tx.Data, err = r.readTransactionData()
+	if err != nil {
+		return
+	}
+
+	tx.Fee, err = r.readU64()
+	if err != nil {
+		return
+	}
+
+	tx.Nonce, err = r.readU64()
+	if err != nil {
+		return
+	}
+
+	count, err := r.readU8()
+	if err != nil {
+		return
+	}
+
+	tx.SourceCommitments = make([]SourceCommitment, count)
+	for i := range tx.SourceCommitments {
+		tx.SourceCommitments[i], err = r.readSourceCommitment()
+		if err != nil {
+			return
+		}
+	}
+
+	tx.RangeProof, err = r.readVec()
+	if err != nil {
+		return
+	}
+
+	err = r.readFixed(tx.Reference.Hash[:])
+	if err != nil {
+		return
+	}
+
+	tx.Reference.Topoheight, err = r.readU64()
+	if err != nil {
+		return
+	}
+
+	err = r.readFixed(tx.Signature[:])
+	return
+}
+
+func (r *Reader) readTransactionData() (data TransactionData, err error) {
+	tag, err := r.readU8()
+	if err != nil {
+		return
+	}
+
+	switch tag {
+	case BurnTag:
+		burn := &Burn{}
+		err = r.readFixed(burn.Asset[:])
+		if err != nil {
+			return
+		}
+
+		burn.Amount, err = r.readU64()
+		if err != nil {
+			return
+		}
+
+		data.Burn = burn
+	case TransfersTag:
+		var count uint8
+		count, err = r.readU8()
+		if err != nil {
+			return
+		}
+
+		data.Transfers = make([]Transfer, count)
+		for i := range data.Transfers {
+			data.Transfers[i], err = r.readTransfer()
+			if err != nil {
+				return
+			}
+		}
+	default:
+		err = ErrInvalidTag("transaction data", tag)
+	}
+
+	return
+}
+
+func (r *Reader) readTransfer() (transfer Transfer, err error) {
+	err = r.readFixed(transfer.Asset[:])
+	if err != nil {
+		return
+	}
+
+	err = r.readFixed(transfer.Destination[:])
+	if err != nil {
+		return
+	}
+
+	hasExtraData, err := r.readBool()
+	if err != nil {
+		return
+	}
+
+	if hasExtraData {
+		var extraData []byte
+		extraData, err = r.readVec()
+		if err != nil {
+			return
+		}
+
+		transfer.ExtraData = &extraData
+	}
+
+	for _, point := range []*CompressedPoint{&transfer.Commitment, &transfer.SenderHandle, &transfer.ReceiverHandle} {
+		err = r.readFixed(point[:])
+		if err != nil {
+			return
+		}
+	}
+
+	proof := &transfer.CTValidityProof
+	for _, value := range [][]byte{proof.Y_0[:], proof.Y_1[:], proof.Z_R[:], proof.Z_X[:]} {
+		err = r.readFixed(value)
+		if err != nil {
+			return
+		}
+	}
+
+	return
+}
+
+func (r *Reader) readSourceCommitment() (commitment SourceCommitment, err error) {
+	err = r.readFixed(commitment.Commitment[:])
+	if err != nil {
+		return
+	}
+
+	proof := &commitment.Proof
+	for _, value := range [][]byte{proof.Y_0[:], proof.Y_1[:], proof.Y_2[:], proof.Z_R[:], proof.Z_S[:], proof.Z_X[:]} {
+		err = r.readFixed(value)
+		if err != nil {
+			return
+		}
+	}
+
+	err = r.readFixed(commitment.Asset[:])
+	return
+}
+
+func (w *Writer) WriteTransaction(tx Transaction) (err error) {
+	err = w.writeU8(tx.Version)
+	if err != nil {
+		return
+	}
+
+	err = w.writeBytes(tx.Source[:])
+	if err != nil {
+		return
+	}
+
+	err = w.writeTransactionData(tx.Data)
+	if err != nil {
+		return
+	}
+
+	err = w.writeU64(tx.Fee)
+	if err != nil {
+		return
+	}
+
+	err = w.writeU64(tx.Nonce)
+	if err != nil {
+		return
+	}
+
+	err = w.writeCount("source commitments", len(tx.SourceCommitments))
+	if err != nil {
+		return
+	}
+
+	for _, commitment := range tx.SourceCommitments {
+		err = w.writeSourceCommitment(commitment)
+		if err != nil {
+			return
+		}
+	}
+
+	err = w.writeVec(tx.RangeProof)
+	if err != nil {
+		return
+	}
+
+	err = w.writeBytes(tx.Reference.Hash[:])
+	if err != nil {
+		return
+	}
+
+	err = w.writeU64(tx.Reference.Topoheight)
+	if err != nil {
+		return
+	}
+
+	return w.writeBytes(tx.Signature[:])
+}
+
+func (w *Writer) writeTransactionData(data TransactionData) (err error) {
+	if data.Burn != nil && data.Transfers != nil {
+		err = ErrTransfersAndBurn
+		return
+	}
+
+	if data.Burn != nil {
+		err = w.writeU8(BurnTag)
+		if err != nil {
+			return
+		}
+
+		err = w.writeBytes(data.Burn.Asset[:])
+		if err != nil {
+			return
+		}
+
+		return w.writeU64(data.Burn.Amount)
+	}
+
+	err = w.writeU8(TransfersTag)
+	if err != nil {
+		return
+	}
+
+	err = w.writeCount("transfers", len(data.Transfers))
+	if err != nil {
+		return
+	}
+
+	for _, transfer := range data.Transfers {
+		err = w.writeTransfer(transfer)
+		if err != nil {
+			return
+		}
+	}
+
+	return
+}
+
+func (w *Writer) writeTransfer(transfer Transfer) (err error) {
+	err = w.writeBytes(transfer.Asset[:])
+	if err != nil {
+		return
+	}
+
+	err = w.writeBytes(transfer.Destination[:])
+	if err != nil {
+		return
+	}
+
+	err = w.writeBool(transfer.ExtraData != nil)
+	if err != nil {
+		return
+	}
+
+	if transfer.ExtraData != nil {
+		err = w.writeVec(*transfer.ExtraData)
+		if err != nil {
+			return
+		}
+	}
+
+	proof := transfer.CTValidityProof
+	for _, value := range [][]byte{
+		transfer.Commitment[:], transfer.SenderHandle[:], transfer.ReceiverHandle[:],
+		proof.Y_0[:], proof.Y_1[:], proof.Z_R[:], proof.Z_X[:],
+	} {
+		err = w.writeBytes(value)
+		if err != nil {
+			return
+		}
+	}
+
+	return
+}
+
+func (w *Writer) writeSourceCommitment(commitment SourceCommitment) (err error) {
+	proof := commitment.Proof
+	for _, value := range [][]byte{
+		commitment.Commitment[:],
+		proof.Y_0[:], proof.Y_1[:], proof.Y_2[:], proof.Z_R[:], proof.Z_S[:], proof.Z_X[:],
+		commitment.Asset[:],
+	} {
+		err = w.writeBytes(value)
+		if err != nil {
+			return
+		}
+	}
+
+	return
+}
@@ -0,0 +1,201 @@
+package transaction
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"reflect"
+	"testing"
+)
+
+func fill(value []byte, b byte) {
+	for i := range value {
+		value[i] = b
+	}
+}
+
+func testTransfersTx() Transaction {
+	extraData := []byte("invoice 42")
+	tx := Transaction{
+		Version:    1,
+		Fee:        25000,
+		Nonce:      7,
+		RangeProof: bytes.Repeat([]byte{0xAB}, 672),
+		Reference:  Reference{Topoheight: 1234},
+	}
+	fill(tx.Source[:], 1)
+	fill(tx.Reference.Hash[:], 2)
+	fill(tx.Signature[:], 3)
+
+	for i := 0; i < 2; i++ {
+		transfer := Transfer{}
+		fill(transfer.Destination[:], byte(10+i))
+		fill(transfer.Commitment[:], byte(20+i))
+		fill(transfer.CTValidityProof.Z_X[:], byte(30+i))
+		if i == 1 {
+			transfer.ExtraData = &extraData
+		}
+
+		tx.Data.Transfers = append(tx.Data.Transfers, transfer)
+	}
+
+	commitment := SourceCommitment{}
+	fill(commitment.Commitment[:], 4)
+	fill(commitment.Proof.Y_2[:], 5)
+	tx.SourceCommitments = append(tx.SourceCommitments, commitment)
+
+	return tx
+}
+
+func TestTransfersRoundTrip(t *testing.T) {
+	tx := testTransfersTx()
+	data, err := tx.Encode()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	transferSize := 32 + 32 + 1 + 3*32 + 128
+	size := 1 + 32 + 1 + 1 + 2*transferSize + 2 + 10 + 8 + 8 + 1 + 32 + 192 + 32 + 2 + 672 + 32 + 8 + 64
+	if len(data) != size {
+		t.Fatalf("Expected %d bytes, got %d", size, len(data))
+	}
+
+	decoded, err := Decode(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(tx, decoded) {
+		t.Errorf("Decoded transaction is different: %+v", decoded)
+	}
+
+	value, err := decoded.EncodeHex()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	decoded, err = DecodeHex(value)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	reencoded, _ := decoded.Encode()
+	if !bytes.Equal(data, reencoded) {
+		t.Error("Re-encoded transaction is different")
+	}
+}
+
+func TestBurnRoundTrip(t *testing.T) {
+	tx := Transaction{Data: TransactionData{Burn: &Burn{Amount: 100}}, RangeProof: []byte{}, SourceCommitments: []SourceCommitment{}}
+	fill(tx.Data.Burn.Asset[:], 9)
+
+	data, err := tx.Encode()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// version, source, tag, asset, amount
+	if data[33] != BurnTag || data[34] != 9 || data[73] != 100 {
+		t.Errorf("Unexpected burn encoding %x", data[:74])
+	}
+
+	decoded, err := Decode(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(tx, decoded) {
+		t.Errorf("Decoded transaction is different: %+v", decoded)
+	}
+}
+
+func TestInvalidData(t *testing.T) {
+	tx := testTransfersTx()
+	data, err := tx.Encode()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, size := range []int{0, 1, 33, 40, len(data) - 1} {
+		_, err = Decode(data[:size])
+		if !errors.Is(err, io.ErrUnexpectedEOF) {
+			t.Errorf("Expected unexpected EOF for %d bytes, got %v", size, err)
+		}
+	}
+
+	_, err = Decode(append(data, 0))
+	if !errors.Is(err, ErrTrailingBytes) {
+		t.Errorf("Expected trailing bytes error, got %v", err)
+	}
+
+	invalid := append([]byte{}, data...)
+	invalid[33] = 5
+	_, err = Decode(invalid)
+	if err == nil {
+		t.Error("Expected invalid tag error")
+	}
+
+	tx.Data.Burn = &Burn{}
+	_, err = tx.Encode()
+	if !errors.Is(err, ErrTransfersAndBurn) {
+		t.Errorf("Expected transfers and burn error, got %v", err)
+	}
+}
+
+// Bytes written out field by field, so the layout is checked without the encoder.
+func TestTransferLayout(t *testing.T) {
+	repeat := func(b byte, count int) []byte {
+		return bytes.Repeat([]byte{b}, count)
+	}
+
+	var expected []byte
+	expected = append(expected, 1)                   // version
+	expected = append(expected, repeat(0x11, 32)...) // source
+	expected = append(expected, TransfersTag, 1)     // one transfer
+	expected = append(expected, repeat(0x21, 32)...) // asset
+	expected = append(expected, repeat(0x22, 32)...) // destination
+	expected = append(expected, 1, 0, 2, 'h', 'i')   // extra data, u16 size
+	for b := byte(0x23); b <= 0x29; b++ {
+		// commitment, sender and receiver handles, Y_0, Y_1, Z_R and Z_X
+		expected = append(expected, repeat(b, 32)...)
+	}
+	expected = append(expected, 0, 0, 0, 0, 0, 0, 0x61, 0xa8) // fee
+	expected = append(expected, 0, 0, 0, 0, 0, 0, 0, 3)       // nonce
+	expected = append(expected, 1)                            // one source commitment
+	for b := byte(0x31); b <= 0x38; b++ {
+		// commitment, Y_0, Y_1, Y_2, Z_R, Z_S, Z_X and asset
+		expected = append(expected, repeat(b, 32)...)
+	}
+	expected = append(expected, 0, 3, 0xaa, 0xbb, 0xcc)       // range proof, u16 size
+	expected = append(expected, repeat(0x41, 32)...)          // reference hash
+	expected = append(expected, 0, 0, 0, 0, 0, 0, 0x04, 0xd2) // reference topoheight
+	expected = append(expected, repeat(0x51, 64)...)          // signature
+
+	tx, err := Decode(expected)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	transfer := tx.Data.Transfers[0]
+	if tx.Version != 1 || tx.Source[0] != 0x11 || tx.Fee != 25000 || tx.Nonce != 3 || tx.Reference.Topoheight != 1234 {
+		t.Errorf("Unexpected transaction %+v", tx)
+	}
+
+	if transfer.Asset[0] != 0x21 || string(*transfer.ExtraData) != "hi" || transfer.ReceiverHandle[0] != 0x25 || transfer.CTValidityProof.Z_X[0] != 0x29 {
+		t.Errorf("Unexpected transfer %+v", transfer)
+	}
+
+	commitment := tx.SourceCommitments[0]
+	if commitment.Proof.Z_X[0] != 0x37 || commitment.Asset[0] != 0x38 || !bytes.Equal(tx.RangeProof, []byte{0xaa, 0xbb, 0xcc}) {
+		t.Errorf("Unexpected source commitment %+v", commitment)
+	}
+
+	data, err := tx.Encode()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.Equal(data, expected) {
+		t.Errorf("Expected %x, got %x", expected, data)
+	}
+}
@@ -0,0 +1,49 @@
+package wallet
+
+import "context"
+
+// Client is the set of wallet calls available on both RPC and WebSocket.
+// Use it to swap the transport or inject a fake in tests.
+type Client interface {
+	GetVersion() (version string, err error)
+	GetVersionContext(ctx context.Context) (version string, err error)
+	GetNetwork() (network string, err error)
+	GetNetworkContext(ctx context.Context) (network string, err error)
+	GetNonce() (nonce uint64, err error)
+	GetNonceContext(ctx context.Context) (nonce uint64, err error)
+	GetTopoheight() (topoheight uint64, err error)
+	GetTopoheightContext(ctx context.Context) (topoheight uint64, err error)
+	GetAddress(params GetAddressParams) (address string, err error)
+	GetAddressContext(ctx context.Context, params GetAddressParams) (address string, err error)
+	SplitAddress(params SplitAddressParams) (result SplitAddressResult, err error)
+	SplitAddressContext(ctx context.Context, params SplitAddressParams) (result SplitAddressResult, err error)
+	Rescan(params RescanParams) (success bool, err error)
+	RescanContext(ctx context.Context, params RescanParams) (success bool, err error)
+	GetBalance(params GetBalanceParams) (balance uint64, err error)
+	GetBalanceContext(ctx context.Context, params GetBalanceParams) (balance uint64, err error)
+	HasBalance(params GetBalanceParams) (exists bool, err error)
+	HasBalanceContext(ctx context.Context, params GetBalanceParams) (exists bool, err error)
+	GetTrackedAssets() (assets []string, err error)
+	GetTrackedAssetsContext(ctx context.Context) (assets []string, err error)
+	GetAssetPrecision(params GetAssetPrecisionParams) (decimals int, err error)
+	GetAssetPrecisionContext(ctx context.Context, params GetAssetPrecisionParams) (decimals int, err error)
+	GetTransaction(params GetTransactionParams) (transaction TransactionEntry, err error)
+	GetTransactionContext(ctx context.Context, params GetTransactionParams) (transaction TransactionEntry, err error)
+	BuildTransaction(params BuildTransactionParams) (result BuildTransactionResult, err error)
+	BuildTransactionContext(ctx context.Context, params BuildTransactionParams) (result BuildTransactionResult, err error)
+	ListTransactions(params ListTransactionsParams) (txs []TransactionEntry, err error)
+	ListTransactionsContext(ctx context.Context, params ListTransactionsParams) (txs []TransactionEntry, err error)
+	IsOnline() (online bool, err error)
+	IsOnlineContext(ctx context.Context) (online bool, err error)
+	SetOnlineMode() (success bool, err error)
+	SetOnlineModeContext(ctx context.Context) (success bool, err error)
+	SetOfflineMode() (success bool, err error)
+	SetOfflineModeContext(ctx context.Context) (success bool, err error)
+	SignData(data interface{}) (signature string, err error)
+	SignDataContext(ctx context.Context, data interface{}) (signature string, err error)
+	EstimateFees(params EstimateFeesParams) (amount uint64, err error)
+	EstimateFeesContext(ctx context.Context, params EstimateFeesParams) (amount uint64, err error)
+}
+
+var _ Client = (*RPC)(nil)
+var _ Client = (*WebSocket)(nil)
@@ -0,0 +1,14 @@
+package wallet
+
+import "github.com/xelis-project/xelis-go-sdk/rpc"
+
+// The wallet reports these failures with the internal error code, so they are matched on the message too.
+// Use them with errors.Is on errors returned by RPC and WebSocket.
+var (
+	ErrBalanceNotFound     = &rpc.RPCError{Code: rpc.InternalErrorCode, Message: "balance not found"}
+	ErrAssetNotFound       = &rpc.RPCError{Code: rpc.InternalErrorCode, Message: "asset not found"}
+	ErrTransactionNotFound = &rpc.RPCError{Code: rpc.InternalErrorCode, Message: "transaction not found"}
+	ErrInsufficientFunds   = &rpc.RPCError{Code: rpc.InternalErrorCode, Message: "insufficient funds"}
+	ErrAlreadyOnline       = &rpc.RPCError{Code: rpc.InternalErrorCode, Message: "already in online mode"}
+	ErrAlreadyOffline      = &rpc.RPCError{Code: rpc.InternalErrorCode, Message: "already in offline mode"}
+)
@@ -10,6 +10,7 @@ import (
 
 	"github.com/creachadair/jrpc2"
 	"github.com/creachadair/jrpc2/jhttp"
+	"github.com/xelis-project/xelis-go-sdk/rpc"
 )
 
 type RPC struct {
@@ -63,102 +64,184 @@ func NewRPC(ctx context.Context, url string, username string, password string) (
 	return daemon, nil
 }
 
+// Same as Client.CallResult, with server errors returned as *rpc.RPCError like the WebSocket client.
+func (d *RPC) callResult(ctx context.Context, method string, params interface{}, result interface{}) error {
+	err := d.Client.CallResult(ctx, method, params, result)
+	return rpc.FromJRPC2Error(err)
+}
+
 func (d *RPC) GetVersion() (version string, err error) {
-	err = d.Client.CallResult(d.ctx, string(GetVersion), nil, &version)
+	return d.GetVersionContext(d.ctx)
+}
+
+func (d *RPC) GetVersionContext(ctx context.Context) (version string, err error) {
+	err = d.callResult(ctx, string(GetVersion), nil, &version)
 	return
 }
 
 func (d *RPC) GetNetwork() (network string, err error) {
-	err = d.Client.CallResult(d.ctx, string(GetNetwork), nil, &network)
+	return d.GetNetworkContext(d.ctx)
+}
+
+func (d *RPC) GetNetworkContext(ctx context.Context) (network string, err error) {
+	err = d.callResult(ctx, string(GetNetwork), nil, &network)
 	return
 }
 
 func (d *RPC) GetNonce() (nonce uint64, err error) {
-	err = d.Client.CallResult(d.ctx, string(GetNonce), nil, &nonce)
+	return d.GetNonceContext(d.ctx)
+}
+
+func (d *RPC) GetNonceContext(ctx context.Context) (nonce uint64, err error) {
+	err = d.callResult(ctx, string(GetNonce), nil, &nonce)
 	return
 }
 
 func (d *RPC) GetTopoheight() (topoheight uint64, err error) {
-	err = d.Client.CallResult(d.ctx, string(GetTopoheight), nil, &topoheight)
+	return d.GetTopoheightContext(d.ctx)
+}
+
+func (d *RPC) GetTopoheightContext(ctx context.Context) (topoheight uint64, err error) {
+	err = d.callResult(ctx, string(GetTopoheight), nil, &topoheight)
 	return
 }
 
 func (d *RPC) GetAddress(params GetAddressParams) (address string, err error) {
-	err = d.Client.CallResult(d.ctx, string(GetAddress), params, &address)
+	return d.GetAddressContext(d.ctx, params)
+}
+
+func (d *RPC) GetAddressContext(ctx context.Context, params GetAddressParams) (address string, err error) {
+	err = d.callResult(ctx, string(GetAddress), params, &address)
 	return
 }
 
 func (d *RPC) SplitAddress(params SplitAddressParams) (result SplitAddressResult, err error) {
-	err = d.Client.CallResult(d.ctx, string(SplitAddress), params, &result)
+	return d.SplitAddressContext(d.ctx, params)
+}
+
+func (d *RPC) SplitAddressContext(ctx context.Context, params SplitAddressParams) (result SplitAddressResult, err error) {
+	err = d.callResult(ctx, string(SplitAddress), params, &result)
 	return
 }
 
 func (d *RPC) Rescan(params RescanParams) (success bool, err error) {
-	err = d.Client.CallResult(d.ctx, string(Rescan), params, &success)
+	return d.RescanContext(d.ctx, params)
+}
+
+func (d *RPC) RescanContext(ctx context.Context, params RescanParams) (success bool, err error) {
+	err = d.callResult(ctx, string(Rescan), params, &success)
 	return
 }
 
 func (d *RPC) GetBalance(params GetBalanceParams) (balance uint64, err error) {
-	err = d.Client.CallResult(d.ctx, string(GetBalance), params, &balance)
+	return d.GetBalanceContext(d.ctx, params)
+}
+
+func (d *RPC) GetBalanceContext(ctx context.Context, params GetBalanceParams) (balance uint64, err error) {
+	err = d.callResult(ctx, string(GetBalance), params, &balance)
 	return
 }
 
 func (d *RPC) HasBalance(params GetBalanceParams) (exists bool, err error) {
-	err = d.Client.CallResult(d.ctx, string(HasBalance), params, &exists)
+	return d.HasBalanceContext(d.ctx, params)
+}
+
+func (d *RPC) HasBalanceContext(ctx context.Context, params GetBalanceParams) (exists bool, err error) {
+	err = d.callResult(ctx, string(HasBalance), params, &exists)
 	return
 }
 
 func (d *RPC) GetTrackedAssets() (assets []string, err error) {
-	err = d.Client.CallResult(d.ctx, string(GetTrackedAssets), nil, &assets)
+	return d.GetTrackedAssetsContext(d.ctx)
+}
+
+func (d *RPC) GetTrackedAssetsContext(ctx context.Context) (assets []string, err error) {
+	err = d.callResult(ctx, string(GetTrackedAssets), nil, &assets)
 	return
 }
 
 func (d *RPC) GetAssetPrecision(params GetAssetPrecisionParams) (decimals int, err error) {
-	err = d.Client.CallResult(d.ctx, string(GetAssetPrecision), params, &decimals)
+	return d.GetAssetPrecisionContext(d.ctx, params)
+}
+
+func (d *RPC) GetAssetPrecisionContext(ctx context.Context, params GetAssetPrecisionParams) (decimals int, err error) {
+	err = d.callResult(ctx, string(GetAssetPrecision), params, &decimals)
 	return
 }
 
 func (d *RPC) GetTransaction(params GetTransactionParams) (transaction TransactionEntry, err error) {
-	err = d.Client.CallResult(d.ctx, string(GetTransaction), params, &transaction)
+	return d.GetTransactionContext(d.ctx, params)
+}
+
+func (d *RPC) GetTransactionContext(ctx context.Context, params GetTransactionParams) (transaction TransactionEntry, err error) {
+	err = d.callResult(ctx, string(GetTransaction), params, &transaction)
 	return
 }
 
 func (d *RPC) BuildTransaction(params BuildTransactionParams) (result BuildTransactionResult, err error) {
+	return d.BuildTransactionContext(d.ctx, params)
+}
+
+func (d *RPC) BuildTransactionContext(ctx context.Context, params BuildTransactionParams) (result BuildTransactionResult, err error) {
 	if err = checkFeeBuilder(params.Fee); err != nil {
 		return
 	}
 
-	err = d.Client.CallResult(d.ctx, string(BuildTransaction), params, &result)
+	err = d.callResult(ctx, string(BuildTransaction), params, &result)
 	return
 }
 
 func (d *RPC) ListTransactions(params ListTransactionsParams) (txs []TransactionEntry, err error) {
-	err = d.Client.CallResult(d.ctx, string(ListTransactions), params, &txs)
+	return d.ListTransactionsContext(d.ctx, params)
+}
+
+func (d *RPC) ListTransactionsContext(ctx context.Context, params ListTransactionsParams) (txs []TransactionEntry, err error) {
+	err = d.callResult(ctx, string(ListTransactions), params, &txs)
 	return
 }
 
 func (d *RPC) IsOnline() (online bool, err error) {
-	err = d.Client.CallResult(d.ctx, string(IsOnline), nil, &online)
+	return d.IsOnlineContext(d.ctx)
+}
+
+func (d *RPC) IsOnlineContext(ctx context.Context) (online bool, err error) {
+	err = d.callResult(ctx, string(IsOnline), nil, &online)
 	return
 }
 
 func (d *RPC) SetOnlineMode() (success bool, err error) {
-	err = d.Client.CallResult(d.ctx, string(SetOnlineMode), nil, &success)
+	return d.SetOnlineModeContext(d.ctx)
+}
+
+func (d *RPC) SetOnlineModeContext(ctx context.Context) (success bool, err error) {
+	err = d.callResult(ctx, string(SetOnlineMode), nil, &success)
 	return
 }
 
 func (d *RPC) SetOfflineMode() (success bool, err error) {
-	err = d.Client.CallResult(d.ctx, string(SetOfflineMode), nil, &success)
+	return d.SetOfflineModeContext(d.ctx)
+}
+
+func (d *RPC) SetOfflineModeContext(ctx context.Context) (success bool, err error) {
+	err = d.callResult(ctx, string(SetOfflineMode), nil, &success)
 	return
 }
 
 func (d *RPC) SignData(data interface{}) (signature string, err error) {
-	err = d.Client.CallResult(d.ctx, string(SignData), data, &signature)
+	return d.SignDataContext(d.ctx, data)
+}
+
+func (d *RPC) SignDataContext(ctx context.Context, data interface{}) (signature string, err error) {
+	err = d.callResult(ctx, string(SignData), data, &signature)
 	return
 }
 
 func (d *RPC) EstimateFees(params EstimateFeesParams) (amount uint64, err error) {
-	err = d.Client.CallResult(d.ctx, string(EstimateFees), params, &amount)
+	return d.EstimateFeesContext(d.ctx, params)
+}
+
+func (d *RPC) EstimateFeesContext(ctx context.Context, params EstimateFeesParams) (amount uint64, err error) {
+	err = d.callResult(ctx, string(EstimateFees), params, &amount)
 	return
 }
 
@@ -1,6 +1,7 @@
 package wallet
 
 import (
+	"github.com/xelis-project/xelis-go-sdk/address"
 	"github.com/xelis-project/xelis-go-sdk/daemon"
 )
 
@@ -13,8 +14,8 @@ type SplitAddressParams struct {
 }
 
 type SplitAddressResult struct {
-	Address        string      `json:"address"`
-	IntegratedData interface{} `json:"integrated_data"`
+	Address        string              `json:"address"`
+	IntegratedData address.DataElement `json:"integrated_data"`
 }
 
 type GetBalanceParams struct {
@@ -34,9 +35,9 @@ type GetAssetPrecisionParams struct {
 }
 
 type TransferIn struct {
-	Amount    uint64       `json:"amount"`
-	Asset     string       `json:"asset"`
-	ExtraData *interface{} `json:"extra_data"`
+	Amount    uint64               `json:"amount"`
+	Asset     string               `json:"asset"`
+	ExtraData *address.DataElement `json:"extra_data"`
 }
 
 /*
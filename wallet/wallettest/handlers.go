@@ -0,0 +1,463 @@
+package wallettest
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/xelis-project/xelis-go-sdk/address"
+	"github.com/xelis-project/xelis-go-sdk/config"
+	"github.com/xelis-project/xelis-go-sdk/daemon"
+	"github.com/xelis-project/xelis-go-sdk/rpc"
+	"github.com/xelis-project/xelis-go-sdk/rpc/rpctest"
+	"github.com/xelis-project/xelis-go-sdk/transaction"
+	"github.com/xelis-project/xelis-go-sdk/wallet"
+)
+
+type noParams struct{}
+
+// Register a method reading its params into P. The ledger is locked while fn runs.
+func handle[P any](s *Server, method string, fn func(params P) (interface{}, *rpc.RPCError)) {
+	s.Register(method, func(raw json.RawMessage) (interface{}, *rpc.RPCError) {
+		var params P
+		err := rpctest.ParseParams(raw, &params)
+		if err != nil {
+			return nil, err
+		}
+
+		defer s.mutex.Unlock()
+		s.mutex.Lock()
+		return fn(params)
+	})
+}
+
+func parseAddress(addr string) (*address.Address, *rpc.RPCError) {
+	result, err := address.NewAddressFromString(addr)
+	if err != nil || result == nil {
+		return nil, rpctest.InvalidParams(fmt.Errorf("invalid address %s", addr))
+	}
+
+	return result, nil
+}
+
+func (s *Server) registerHandlers() {
+	handle(s, wallet.GetVersion, func(noParams) (interface{}, *rpc.RPCError) {
+		return s.Version, nil
+	})
+
+	handle(s, wallet.GetNetwork, func(noParams) (interface{}, *rpc.RPCError) {
+		return s.Network, nil
+	})
+
+	handle(s, wallet.GetNonce, func(noParams) (interface{}, *rpc.RPCError) {
+		return s.nonce, nil
+	})
+
+	handle(s, wallet.GetTopoheight, func(noParams) (interface{}, *rpc.RPCError) {
+		return s.topoheight, nil
+	})
+
+	// typed integrated data instead of the interface{} of wallet.GetAddressParams
+	type getAddressParams struct {
+		IntegratedData *address.DataElement `json:"integrated_data"`
+	}
+
+	handle(s, wallet.GetAddress, func(params getAddressParams) (interface{}, *rpc.RPCError) {
+		if params.IntegratedData == nil {
+			return s.Address, nil
+		}
+
+		addr, rpcErr := parseAddress(s.Address)
+		if rpcErr != nil {
+			return nil, rpcErr
+		}
+
+		addr.SetExtraData(params.IntegratedData)
+		integrated, err := addr.Format()
+		if err != nil {
+			return nil, rpctest.InvalidParams(err)
+		}
+
+		return integrated, nil
+	})
+
+	handle(s, wallet.SplitAddress, func(params wallet.SplitAddressParams) (interface{}, *rpc.RPCError) {
+		addr, rpcErr := parseAddress(params.Address)
+		if rpcErr != nil {
+			return nil, rpcErr
+		}
+
+		if !addr.IsIntegrated() {
+			return nil, rpctest.InvalidParams(fmt.Errorf("address is not integrated"))
+		}
+
+		integratedData := *addr.GetExtraData()
+		addr.ClearExtraData()
+		plain, err := addr.Format()
+		if err != nil {
+			return nil, rpctest.NewError(rpc.InternalErrorCode, "%s", err)
+		}
+
+		return wallet.SplitAddressResult{Address: plain, IntegratedData: integratedData}, nil
+	})
+
+	s.Register(wallet.Rescan, func(raw json.RawMessage) (interface{}, *rpc.RPCError) {
+		var params wallet.RescanParams
+		err := rpctest.ParseParams(raw, &params)
+		if err != nil {
+			return nil, err
+		}
+
+		s.mutex.Lock()
+		topoheight := s.topoheight
+		s.mutex.Unlock()
+
+		if params.UntilTopoheight > topoheight {
+			return nil, rpctest.InvalidParams(fmt.Errorf("topoheight %d is above the wallet topoheight %d", params.UntilTopoheight, topoheight))
+		}
+
+		s.EmitRescan(params.UntilTopoheight)
+		return true, nil
+	})
+
+	handle(s, wallet.GetBalance, func(params wallet.GetBalanceParams) (interface{}, *rpc.RPCError) {
+		asset := params.Asset
+		if asset == "" {
+			asset = config.XELIS_ASSET
+		}
+
+		balance, ok := s.balances[asset]
+		if !ok {
+			return nil, rpctest.NewError(rpc.InternalErrorCode, "Balance not found for asset %s", asset)
+		}
+
+		return balance, nil
+	})
+
+	handle(s, wallet.HasBalance, func(params wallet.GetBalanceParams) (interface{}, *rpc.RPCError) {
+		asset := params.Asset
+		if asset == "" {
+			asset = config.XELIS_ASSET
+		}
+
+		_, ok := s.balances[asset]
+		return ok, nil
+	})
+
+	handle(s, wallet.GetTrackedAssets, func(noParams) (interface{}, *rpc.RPCError) {
+		assets := make([]string, 0, len(s.assets))
+		for asset := range s.assets {
+			assets = append(assets, asset)
+		}
+
+		sort.Strings(assets)
+		return assets, nil
+	})
+
+	handle(s, wallet.GetAssetPrecision, func(params wallet.GetAssetPrecisionParams) (interface{}, *rpc.RPCError) {
+		decimals, ok := s.assets[params.Asset]
+		if !ok {
+			return nil, rpctest.NewError(rpc.InternalErrorCode, "Asset not found: %s", params.Asset)
+		}
+
+		return decimals, nil
+	})
+
+	handle(s, wallet.GetTransaction, func(params wallet.GetTransactionParams) (interface{}, *rpc.RPCError) {
+		for _, entry := range s.txs {
+			if entry.Hash == params.Hash {
+				return entry, nil
+			}
+		}
+
+		return nil, rpctest.NewError(rpc.InternalErrorCode, "Transaction not found: %s", params.Hash)
+	})
+
+	s.Register(wallet.BuildTransaction, func(raw json.RawMessage) (interface{}, *rpc.RPCError) {
+		var params wallet.BuildTransactionParams
+		err := rpctest.ParseParams(raw, &params)
+		if err != nil {
+			return nil, err
+		}
+
+		s.mutex.Lock()
+		result, entry, changed, err := s.buildTransaction(params)
+		s.mutex.Unlock()
+
+		if err != nil {
+			return nil, err
+		}
+
+		if entry != nil {
+			s.notifyTransaction(*entry, changed)
+		}
+
+		return result, nil
+	})
+
+	handle(s, wallet.ListTransactions, func(params wallet.ListTransactionsParams) (interface{}, *rpc.RPCError) {
+		txs := []wallet.TransactionEntry{}
+		for _, entry := range s.txs {
+			if params.MinTopoheight != nil && entry.Topoheight < *params.MinTopoheight {
+				continue
+			}
+
+			if params.MaxTopoheight != nil && entry.Topoheight > *params.MaxTopoheight {
+				continue
+			}
+
+			if acceptEntry(entry, params) {
+				txs = append(txs, entry)
+			}
+		}
+
+		return txs, nil
+	})
+
+	handle(s, wallet.IsOnline, func(noParams) (interface{}, *rpc.RPCError) {
+		return s.online, nil
+	})
+
+	s.Register(wallet.SetOnlineMode, func(json.RawMessage) (interface{}, *rpc.RPCError) {
+		s.mutex.Lock()
+		online := s.online
+		s.mutex.Unlock()
+
+		if online {
+			return nil, rpctest.NewError(rpc.InternalErrorCode, "Wallet is already in online mode")
+		}
+
+		s.SetOnline(true)
+		return true, nil
+	})
+
+	s.Register(wallet.SetOfflineMode, func(json.RawMessage) (interface{}, *rpc.RPCError) {
+		s.mutex.Lock()
+		online := s.online
+		s.mutex.Unlock()
+
+		if !online {
+			return nil, rpctest.NewError(rpc.InternalErrorCode, "Wallet is already in offline mode")
+		}
+
+		s.SetOnline(false)
+		return true, nil
+	})
+
+	handle(s, wallet.SignData, func(data address.DataElement) (interface{}, *rpc.RPCError) {
+		var message bytes.Buffer
+		writer := address.DataValueWriter{Writer: &message}
+		err := writer.Write(data)
+		if err != nil {
+			return nil, rpctest.InvalidParams(err)
+		}
+
+		signature, err := s.PrivateKey.Sign(message.Bytes())
+		if err != nil {
+			return nil, rpctest.NewError(rpc.InternalErrorCode, "%s", err)
+		}
+
+		return signature.Hex(), nil
+	})
+
+	handle(s, wallet.EstimateFees, func(params wallet.EstimateFeesParams) (interface{}, *rpc.RPCError) {
+		transfers := 0
+		if params.Transfers != nil {
+			transfers = len(*params.Transfers)
+		}
+
+		if transfers == 0 && params.Burn == nil {
+			return nil, rpctest.InvalidParams(fmt.Errorf("no transfers or burn"))
+		}
+
+		return s.fee(transfers), nil
+	})
+}
+
+func (s *Server) buildTransaction(params wallet.BuildTransactionParams) (result wallet.BuildTransactionResult, entry *wallet.TransactionEntry, changed map[string]uint64, rpcErr *rpc.RPCError) {
+	if len(params.Transfers) == 0 && params.Burn == nil {
+		rpcErr = rpctest.InvalidParams(fmt.Errorf("no transfers or burn"))
+		return
+	}
+
+	if len(params.Transfers) > 0 && params.Burn != nil {
+		rpcErr = rpctest.InvalidParams(fmt.Errorf("cannot transfer and burn in the same transaction"))
+		return
+	}
+
+	source, rpcErr := parseAddress(s.Address)
+	if rpcErr != nil {
+		return
+	}
+
+	fee := s.fee(len(params.Transfers))
+	if params.Fee != nil && params.Fee.Value != nil {
+		fee = *params.Fee.Value
+	} else if params.Fee != nil && params.Fee.Multiplier != nil {
+		fee = uint64(math.Ceil(float64(fee) * *params.Fee.Multiplier))
+	}
+
+	spent := map[string]uint64{config.XELIS_ASSET: fee}
+	var transfers []wallet.Transfer
+	for _, transfer := range params.Transfers {
+		destination, err := parseAddress(transfer.Destination)
+		if err != nil {
+			rpcErr = err
+			return
+		}
+
+		if transfer.Amount == 0 {
+			rpcErr = rpctest.InvalidParams(fmt.Errorf("transfer amount cannot be zero"))
+			return
+		}
+
+		spent[transfer.Asset] += transfer.Amount
+		transfers = append(transfers, wallet.Transfer{
+			Asset:       transfer.Asset,
+			Destination: destination.GetPublicKey(),
+		})
+	}
+
+	if params.Burn != nil {
+		if params.Burn.Amount == 0 {
+			rpcErr = rpctest.InvalidParams(fmt.Errorf("burn amount cannot be zero"))
+			return
+		}
+
+		spent[params.Burn.Asset] += params.Burn.Amount
+	}
+
+	for asset, amount := range spent {
+		balance, ok := s.balances[asset]
+		if !ok || balance < amount {
+			rpcErr = rpctest.NewError(rpc.InternalErrorCode, "Insufficient funds for asset %s: required %d, available %d", asset, amount, balance)
+			return
+		}
+	}
+
+	result = wallet.BuildTransactionResult{
+		Data: wallet.TransactionData{
+			Transfers: transfers,
+			Burn:      params.Burn,
+		},
+		Fee:               fee,
+		Hash:              s.newHash(),
+		Nonce:             s.nonce,
+		RangeProof:        []byte{},
+		Reference:         daemon.Reference{Hash: hex.EncodeToString(make([]byte, 32)), Topoheight: s.topoheight},
+		Signature:         hex.EncodeToString(make([]byte, 64)),
+		Source:            source.GetPublicKey(),
+		SourceCommitments: []daemon.SourceCommitment{},
+		Version:           0,
+	}
+
+	if params.TxAsHex {
+		// Serialized with zeroed proofs and signature.
+		result.TxAsHex, rpcErr = encodeTransaction(result)
+		if rpcErr != nil {
+			return
+		}
+	}
+
+	if !params.Broadcast {
+		return
+	}
+
+	s.nonce++
+	built := wallet.TransactionEntry{Hash: result.Hash, Burn: params.Burn}
+	if len(params.Transfers) > 0 {
+		built.Outgoing = &wallet.Outgoing{Fee: fee, Nonce: result.Nonce, Transfers: params.Transfers}
+	} else {
+		s.setBalance(config.XELIS_ASSET, s.balances[config.XELIS_ASSET]-fee)
+	}
+
+	added, changed := s.addTransaction(built)
+	if built.Outgoing == nil {
+		changed[config.XELIS_ASSET] = s.balances[config.XELIS_ASSET]
+	}
+
+	entry = &added
+	return
+}
+
+func decodeHash(value string, hash *transaction.Hash) *rpc.RPCError {
+	data, err := hex.DecodeString(value)
+	if err != nil || len(data) != len(hash) {
+		return rpctest.InvalidParams(fmt.Errorf("invalid hash %s", value))
+	}
+
+	copy(hash[:], data)
+	return nil
+}
+
+func encodeTransaction(result wallet.BuildTransactionResult) (string, *rpc.RPCError) {
+	tx := transaction.Transaction{
+		Version:    uint8(result.Version),
+		Fee:        result.Fee,
+		Nonce:      result.Nonce,
+		RangeProof: result.RangeProof,
+		Reference:  transaction.Reference{Topoheight: result.Reference.Topoheight},
+	}
+	copy(tx.Source[:], result.Source)
+
+	rpcErr := decodeHash(result.Reference.Hash, &tx.Reference.Hash)
+	if rpcErr != nil {
+		return "", rpcErr
+	}
+
+	if result.Data.Burn != nil {
+		tx.Data.Burn = &transaction.Burn{Amount: result.Data.Burn.Amount}
+		rpcErr = decodeHash(result.Data.Burn.Asset, &tx.Data.Burn.Asset)
+		if rpcErr != nil {
+			return "", rpcErr
+		}
+	}
+
+	for _, transfer := range result.Data.Transfers {
+		out := transaction.Transfer{ExtraData: transfer.ExtraData}
+		copy(out.Destination[:], transfer.Destination)
+		rpcErr = decodeHash(transfer.Asset, &out.Asset)
+		if rpcErr != nil {
+			return "", rpcErr
+		}
+
+		tx.Data.Transfers = append(tx.Data.Transfers, out)
+	}
+
+	value, err := tx.EncodeHex()
+	if err != nil {
+		return "", rpctest.NewError(rpc.InternalErrorCode, "%s", err)
+	}
+
+	return value, nil
+}
+
+func acceptEntry(entry wallet.TransactionEntry, params wallet.ListTransactionsParams) bool {
+	switch {
+	case entry.Coinbase != nil:
+		return params.AcceptCoinbase && params.Address == nil
+	case entry.Burn != nil:
+		return params.AcceptBurn && params.Address == nil
+	case entry.Incoming != nil:
+		return params.AcceptIncoming && (params.Address == nil || entry.Incoming.From == *params.Address)
+	case entry.Outgoing != nil:
+		if !params.AcceptOutgoing {
+			return false
+		}
+
+		if params.Address == nil {
+			return true
+		}
+
+		for _, transfer := range entry.Outgoing.Transfers {
+			if transfer.Destination == *params.Address {
+				return true
+			}
+		}
+	}
+
+	return false
+}
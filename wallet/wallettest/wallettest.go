@@ -0,0 +1,277 @@
+// Package wallettest provides an in-process XELIS wallet for tests.
+// It serves the JSON-RPC methods of the wallet package over HTTP and WebSocket from an in-memory ledger.
+package wallettest
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"net/http"
+	"sync"
+
+	"github.com/xelis-project/xelis-go-sdk/config"
+	"github.com/xelis-project/xelis-go-sdk/daemon"
+	"github.com/xelis-project/xelis-go-sdk/keys"
+	"github.com/xelis-project/xelis-go-sdk/rpc/rpctest"
+	"github.com/xelis-project/xelis-go-sdk/wallet"
+)
+
+type Server struct {
+	*rpctest.Server
+
+	// Credentials expected in the Basic auth header. Empty values disable the check.
+	Username string
+	Password string
+
+	Network string
+	Version string
+	// Testnet address of PrivateKey.
+	Address string
+	// Key signing the data of SignData.
+	PrivateKey keys.PrivateKey
+	// Fee paid by a transaction with a single transfer or a burn.
+	BaseFee uint64
+	// Extra fee paid for every transfer after the first one.
+	FeePerTransfer uint64
+
+	mutex      sync.Mutex
+	counter    uint64
+	topoheight uint64
+	nonce      uint64
+	online     bool
+	balances   map[string]uint64
+	assets     map[string]int
+	txs        []wallet.TransactionEntry
+}
+
+// Start a wallet expecting the given credentials, online and tracking XELIS with a zero balance.
+// Call Close when done.
+func NewServer(username string, password string) *Server {
+	privateKey := testingKey()
+	addr, err := privateKey.Address(false).Format()
+	if err != nil {
+		panic(err)
+	}
+
+	s := &Server{
+		Server:         rpctest.NewServer(),
+		Username:       username,
+		Password:       password,
+		Network:        "Testnet",
+		Version:        "1.13.0",
+		Address:        addr,
+		PrivateKey:     privateKey,
+		BaseFee:        1000,
+		FeePerTransfer: 500,
+		online:         true,
+		balances:       make(map[string]uint64),
+		assets:         make(map[string]int),
+	}
+
+	s.assets[config.XELIS_ASSET] = 8
+	s.balances[config.XELIS_ASSET] = 0
+	s.Authorize = s.authorize
+	s.registerHandlers()
+	return s
+}
+
+// Same key on every run, so the address is stable.
+func testingKey() keys.PrivateKey {
+	seed := sha256.Sum256([]byte("wallettest"))
+	// below the group order
+	seed[31] &= 0x0f
+
+	privateKey, err := keys.NewPrivateKey(seed[:])
+	if err != nil {
+		panic(err)
+	}
+
+	return privateKey
+}
+
+func (s *Server) authorize(r *http.Request) bool {
+	if s.Username == "" && s.Password == "" {
+		return true
+	}
+
+	username, password, ok := r.BasicAuth()
+	return ok && username == s.Username && password == s.Password
+}
+
+// Deterministic 32 bytes hex hash, unique for the server.
+func (s *Server) newHash() string {
+	s.counter++
+	data := make([]byte, 8)
+	binary.BigEndian.PutUint64(data, s.counter)
+	hash := sha256.Sum256(append([]byte("wallet"), data...))
+	return hex.EncodeToString(hash[:])
+}
+
+func (s *Server) fee(transfers int) uint64 {
+	if transfers <= 1 {
+		return s.BaseFee
+	}
+
+	return s.BaseFee + uint64(transfers-1)*s.FeePerTransfer
+}
+
+// Set the wallet topoheight and emit new_topo_height.
+func (s *Server) SetTopoheight(topoheight uint64) {
+	s.mutex.Lock()
+	s.topoheight = topoheight
+	s.mutex.Unlock()
+
+	s.EmitNewTopoheight(topoheight)
+}
+
+func (s *Server) SetNonce(nonce uint64) {
+	defer s.mutex.Unlock()
+	s.mutex.Lock()
+	s.nonce = nonce
+}
+
+// Set the balance of an asset and emit balance_changed. The asset is tracked if needed.
+func (s *Server) SetBalance(asset string, balance uint64) {
+	s.mutex.Lock()
+	s.setBalance(asset, balance)
+	s.mutex.Unlock()
+
+	s.EmitBalanceChanged(asset, balance)
+}
+
+func (s *Server) setBalance(asset string, balance uint64) {
+	s.balances[asset] = balance
+	if _, ok := s.assets[asset]; !ok {
+		s.assets[asset] = 8
+	}
+}
+
+// Track a new asset and emit new_asset.
+func (s *Server) AddAsset(asset string, decimals int) {
+	s.mutex.Lock()
+	s.assets[asset] = decimals
+	topoheight := s.topoheight
+	s.mutex.Unlock()
+
+	s.Notify(wallet.NewAsset, daemon.AssetWithData{Asset: asset, Topoheight: topoheight, Decimals: decimals})
+}
+
+// Add an entry to the history and apply it to the balances.
+// Incoming transfers and coinbase rewards are credited, outgoing transfers, burns and fees are debited.
+// Emits new_transaction and balance_changed for every asset touched.
+// A hash is generated if the entry doesn't have one and the current topoheight is used if it is zero.
+func (s *Server) AddTransaction(entry wallet.TransactionEntry) wallet.TransactionEntry {
+	s.mutex.Lock()
+	entry, changed := s.addTransaction(entry)
+	s.mutex.Unlock()
+
+	s.notifyTransaction(entry, changed)
+	return entry
+}
+
+func (s *Server) addTransaction(entry wallet.TransactionEntry) (wallet.TransactionEntry, map[string]uint64) {
+	if entry.Hash == "" {
+		entry.Hash = s.newHash()
+	}
+
+	if entry.Topoheight == 0 {
+		entry.Topoheight = s.topoheight
+	}
+
+	changed := make(map[string]uint64)
+	credit := func(asset string, amount uint64) {
+		s.setBalance(asset, s.balances[asset]+amount)
+		changed[asset] = s.balances[asset]
+	}
+
+	debit := func(asset string, amount uint64) {
+		balance := s.balances[asset]
+		if amount > balance {
+			amount = balance
+		}
+
+		s.setBalance(asset, balance-amount)
+		changed[asset] = s.balances[asset]
+	}
+
+	if entry.Coinbase != nil {
+		credit(config.XELIS_ASSET, entry.Coinbase.Reward)
+	}
+
+	if entry.Incoming != nil {
+		for _, transfer := range entry.Incoming.Transfers {
+			credit(transfer.Asset, transfer.Amount)
+		}
+	}
+
+	if entry.Outgoing != nil {
+		for _, transfer := range entry.Outgoing.Transfers {
+			debit(transfer.Asset, transfer.Amount)
+		}
+
+		debit(config.XELIS_ASSET, entry.Outgoing.Fee)
+	}
+
+	if entry.Burn != nil {
+		debit(entry.Burn.Asset, entry.Burn.Amount)
+	}
+
+	s.txs = append(s.txs, entry)
+	return entry, changed
+}
+
+func (s *Server) notifyTransaction(entry wallet.TransactionEntry, changed map[string]uint64) {
+	s.EmitNewTransaction(entry)
+	for asset, balance := range changed {
+		s.EmitBalanceChanged(asset, balance)
+	}
+}
+
+// Shortcut for AddTransaction with incoming transfers from the given address.
+func (s *Server) Receive(from string, transfers ...wallet.TransferIn) wallet.TransactionEntry {
+	return s.AddTransaction(wallet.TransactionEntry{
+		Incoming: &wallet.Incoming{From: from, Transfers: transfers},
+	})
+}
+
+// Set the network state and emit online or offline if it changed.
+func (s *Server) SetOnline(online bool) {
+	s.mutex.Lock()
+	changed := s.online != online
+	s.online = online
+	s.mutex.Unlock()
+
+	if !changed {
+		return
+	}
+
+	if online {
+		s.EmitOnline()
+	} else {
+		s.EmitOffline()
+	}
+}
+
+func (s *Server) EmitNewTopoheight(topoheight uint64) {
+	s.Notify(wallet.NewTopoheight, map[string]uint64{"topoheight": topoheight})
+}
+
+func (s *Server) EmitNewTransaction(entry wallet.TransactionEntry) {
+	s.Notify(wallet.NewTransaction, entry)
+}
+
+func (s *Server) EmitBalanceChanged(asset string, balance uint64) {
+	s.Notify(wallet.BalanceChanged, wallet.BalanceChangedResult{Asset: asset, Balance: balance})
+}
+
+func (s *Server) EmitRescan(startTopoheight uint64) {
+	s.Notify(wallet.Rescan, map[string]uint64{"start_topoheight": startTopoheight})
+}
+
+func (s *Server) EmitOnline() {
+	s.Notify(wallet.Online, nil)
+}
+
+func (s *Server) EmitOffline() {
+	s.Notify(wallet.Offline, nil)
+}
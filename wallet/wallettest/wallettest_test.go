@@ -0,0 +1,398 @@
+package wallettest
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/xelis-project/xelis-go-sdk/address"
+	"github.com/xelis-project/xelis-go-sdk/config"
+	"github.com/xelis-project/xelis-go-sdk/daemon"
+	"github.com/xelis-project/xelis-go-sdk/keys"
+	"github.com/xelis-project/xelis-go-sdk/transaction"
+	"github.com/xelis-project/xelis-go-sdk/wallet"
+)
+
+const DESTINATION_ADDR = "xet:6eadzwf5xdacts6fs4y3csmnsmy4mcxewqt3xyygwfx0hm0tm32szqsrqyzkjar9d4esyqgpq4ehwmmjvsqqypgpq45x2mrvduqqzpthdaexceqpq4mk7unywvqsgqqpq4yx2mrvduqqzp2hdaexceqqqyzxvun0d5qqzp2cg4xyj5ct5udlg"
+
+func useServer(t *testing.T) (server *Server, ws *wallet.WebSocket, clients map[string]wallet.Client) {
+	server = NewServer("test", "test")
+	t.Cleanup(server.Close)
+
+	rpcClient, err := wallet.NewRPC(context.Background(), server.RPCEndpoint(), "test", "test")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ws, err = wallet.NewWebSocket(server.WSEndpoint(), "test", "test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { ws.Close() })
+
+	clients = map[string]wallet.Client{"rpc": rpcClient, "ws": ws}
+	return
+}
+
+func TestAuthorization(t *testing.T) {
+	server, _, _ := useServer(t)
+
+	rpcClient, err := wallet.NewRPC(context.Background(), server.RPCEndpoint(), "test", "wrong")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = rpcClient.GetVersion()
+	if err == nil {
+		t.Error("Expected an authorization error")
+	}
+
+	_, err = wallet.NewWebSocket(server.WSEndpoint(), "wrong", "test")
+	if err == nil {
+		t.Error("Expected an authorization error")
+	}
+}
+
+func TestGetInfo(t *testing.T) {
+	server, _, clients := useServer(t)
+	server.SetTopoheight(42)
+	server.SetNonce(7)
+
+	for name, client := range clients {
+		version, err := client.GetVersion()
+		if err != nil {
+			t.Fatal(name, err)
+		}
+
+		network, err := client.GetNetwork()
+		if err != nil {
+			t.Fatal(name, err)
+		}
+
+		if version != server.Version || network != server.Network {
+			t.Errorf("%s: unexpected version %s or network %s", name, version, network)
+		}
+
+		topoheight, err := client.GetTopoheight()
+		if err != nil {
+			t.Fatal(name, err)
+		}
+
+		nonce, err := client.GetNonce()
+		if err != nil {
+			t.Fatal(name, err)
+		}
+
+		if topoheight != 42 || nonce != 7 {
+			t.Errorf("%s: unexpected topoheight %d or nonce %d", name, topoheight, nonce)
+		}
+
+		addr, err := client.GetAddress(wallet.GetAddressParams{})
+		if err != nil {
+			t.Fatal(name, err)
+		}
+
+		if addr != server.Address {
+			t.Errorf("%s: unexpected address %s", name, addr)
+		}
+	}
+}
+
+func TestIntegratedAddress(t *testing.T) {
+	server, _, clients := useServer(t)
+
+	var integratedData interface{} = map[string]interface{}{"invoice": 42, "memo": "coffee"}
+	for name, client := range clients {
+		integrated, err := client.GetAddress(wallet.GetAddressParams{IntegratedData: &integratedData})
+		if err != nil {
+			t.Fatal(name, err)
+		}
+
+		split, err := client.SplitAddress(wallet.SplitAddressParams{Address: integrated})
+		if err != nil {
+			t.Fatal(name, err)
+		}
+
+		fields := split.IntegratedData.Fields
+		if split.Address != server.Address || fields["invoice"].Value != uint8(42) || fields["memo"].Value != "coffee" {
+			t.Errorf("%s: unexpected split %+v", name, split)
+		}
+	}
+}
+
+func TestSignData(t *testing.T) {
+	server, _, clients := useServer(t)
+
+	addr, err := address.NewAddressFromString(server.Address)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	fields := make(map[address.DataValue]address.DataElement)
+	fields["hello"] = address.DataElement{Value: "world"}
+	// the wallet reads numbers as the smallest type holding them, the signed bytes use that type
+	fields["amount"] = address.DataElement{Value: uint32(100000)}
+	fields["to"] = address.DataElement{Value: "alice"}
+	// not sorted by encoding, the wallet signs the fields in the order it received them
+	data := address.DataElement{Fields: fields, Keys: []address.DataValue{"amount", "hello", "to"}}
+	sorted := address.DataElement{Fields: fields}
+
+	for name, client := range clients {
+		signature, err := client.SignData(data)
+		if err != nil {
+			t.Fatal(name, err)
+		}
+
+		err = keys.VerifySignData(data, signature, addr)
+		if err != nil {
+			t.Errorf("%s: %s", name, err)
+		}
+
+		err = keys.VerifySignData(sorted, signature, addr)
+		if err != keys.ErrInvalidSignature {
+			t.Errorf("%s: expected an invalid signature with other fields order, got %v", name, err)
+		}
+	}
+}
+
+func TestBalance(t *testing.T) {
+	server, _, clients := useServer(t)
+	server.Receive(DESTINATION_ADDR, wallet.TransferIn{Amount: 5000, Asset: config.XELIS_ASSET})
+
+	for name, client := range clients {
+		balance, err := client.GetBalance(wallet.GetBalanceParams{Asset: config.XELIS_ASSET})
+		if err != nil {
+			t.Fatal(name, err)
+		}
+
+		if balance != 5000 {
+			t.Errorf("%s: expected balance 5000, got %d", name, balance)
+		}
+
+		precision, err := client.GetAssetPrecision(wallet.GetAssetPrecisionParams{Asset: config.XELIS_ASSET})
+		if err != nil {
+			t.Fatal(name, err)
+		}
+
+		if precision != 8 {
+			t.Errorf("%s: expected precision 8, got %d", name, precision)
+		}
+
+		_, err = client.GetBalance(wallet.GetBalanceParams{Asset: "unknown"})
+		if !errors.Is(err, wallet.ErrBalanceNotFound) {
+			t.Errorf("%s: expected unknown asset error", name)
+		}
+	}
+}
+
+func TestBuildTransaction(t *testing.T) {
+	server, _, clients := useServer(t)
+	server.SetBalance(config.XELIS_ASSET, 100000)
+
+	for name, client := range clients {
+		transfers := []wallet.TransferOut{{Amount: 1000, Asset: config.XELIS_ASSET, Destination: DESTINATION_ADDR}}
+		fees, err := client.EstimateFees(wallet.EstimateFeesParams{Transfers: &transfers})
+		if err != nil {
+			t.Fatal(name, err)
+		}
+
+		result, err := client.BuildTransaction(wallet.BuildTransactionParams{Transfers: transfers, Broadcast: true})
+		if err != nil {
+			t.Fatal(name, err)
+		}
+
+		if result.Fee != fees || len(result.Data.Transfers) != 1 || len(result.Data.Transfers[0].Destination) != 32 {
+			t.Errorf("%s: unexpected result %+v", name, result)
+		}
+
+		entry, err := client.GetTransaction(wallet.GetTransactionParams{Hash: result.Hash})
+		if err != nil {
+			t.Fatal(name, err)
+		}
+
+		if entry.Outgoing == nil || entry.Outgoing.Fee != fees {
+			t.Errorf("%s: unexpected entry %+v", name, entry)
+		}
+
+		_, err = client.BuildTransaction(wallet.BuildTransactionParams{
+			Transfers: []wallet.TransferOut{{Amount: 1000000, Asset: config.XELIS_ASSET, Destination: DESTINATION_ADDR}},
+		})
+		if !errors.Is(err, wallet.ErrInsufficientFunds) {
+			t.Errorf("%s: expected insufficient funds error, got %v", name, err)
+		}
+	}
+
+	balance, err := clients["rpc"].GetBalance(wallet.GetBalanceParams{Asset: config.XELIS_ASSET})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := 100000 - 2*(1000+server.BaseFee)
+	if balance != expected {
+		t.Errorf("Expected balance %d, got %d", expected, balance)
+	}
+
+	nonce, err := clients["ws"].GetNonce()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if nonce != 2 {
+		t.Errorf("Expected nonce 2, got %d", nonce)
+	}
+
+	result, err := clients["rpc"].BuildTransaction(wallet.BuildTransactionParams{
+		Burn:    &daemon.Burn{Amount: 500, Asset: config.XELIS_ASSET},
+		TxAsHex: true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tx, err := transaction.DecodeHex(result.TxAsHex)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if tx.Data.Burn == nil || tx.Data.Burn.Amount != 500 || tx.Nonce != 2 || tx.Fee != result.Fee {
+		t.Errorf("Unexpected transaction %+v", tx)
+	}
+}
+
+func TestListTransactions(t *testing.T) {
+	server, _, clients := useServer(t)
+	server.SetTopoheight(10)
+	server.AddTransaction(wallet.TransactionEntry{Coinbase: &wallet.Coinbase{Reward: 100000}})
+	server.SetTopoheight(20)
+	server.Receive(DESTINATION_ADDR, wallet.TransferIn{Amount: 5000, Asset: config.XELIS_ASSET})
+
+	for name, client := range clients {
+		min := uint64(15)
+		txs, err := client.ListTransactions(wallet.ListTransactionsParams{MinTopoheight: &min, AcceptIncoming: true, AcceptCoinbase: true})
+		if err != nil {
+			t.Fatal(name, err)
+		}
+
+		if len(txs) != 1 || txs[0].Incoming == nil {
+			t.Errorf("%s: unexpected transactions %+v", name, txs)
+		}
+
+		txs, err = client.ListTransactions(wallet.ListTransactionsParams{AcceptCoinbase: true})
+		if err != nil {
+			t.Fatal(name, err)
+		}
+
+		if len(txs) != 1 || txs[0].Coinbase == nil || txs[0].Topoheight != 10 {
+			t.Errorf("%s: unexpected transactions %+v", name, txs)
+		}
+	}
+}
+
+func TestOnlineMode(t *testing.T) {
+	_, ws, clients := useServer(t)
+
+	offline, _, err := ws.OfflineChannel()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	online, _, err := ws.OnlineChannel()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	client := clients["rpc"]
+	_, err = client.SetOnlineMode()
+	if !errors.Is(err, wallet.ErrAlreadyOnline) {
+		t.Error("Expected already online error")
+	}
+
+	_, err = client.SetOfflineMode()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case <-offline:
+	case <-time.After(5 * time.Second):
+		t.Fatal("offline was not received")
+	}
+
+	isOnline, err := client.IsOnline()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if isOnline {
+		t.Error("Expected wallet to be offline")
+	}
+
+	_, err = client.SetOnlineMode()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case <-online:
+	case <-time.After(5 * time.Second):
+		t.Fatal("online was not received")
+	}
+}
+
+func TestEvents(t *testing.T) {
+	server, ws, _ := useServer(t)
+
+	topoheights, topoheightErr, err := ws.NewTopoheightChannel()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	txs, txErr, err := ws.NewTransactionChannel()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	balances, balanceErr, err := ws.BalanceChangedChannel()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	go func() {
+		server.SetTopoheight(5)
+		server.Receive(DESTINATION_ADDR, wallet.TransferIn{Amount: 300, Asset: config.XELIS_ASSET})
+	}()
+
+	select {
+	case topoheight := <-topoheights:
+		if topoheight != 5 {
+			t.Errorf("Expected topoheight 5, got %d", topoheight)
+		}
+	case err := <-topoheightErr:
+		t.Fatal(err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("new_topo_height was not received")
+	}
+
+	select {
+	case entry := <-txs:
+		if entry.Incoming == nil || entry.Incoming.From != DESTINATION_ADDR || entry.Topoheight != 5 {
+			t.Errorf("unexpected entry %+v", entry)
+		}
+	case err := <-txErr:
+		t.Fatal(err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("new_transaction was not received")
+	}
+
+	select {
+	case result := <-balances:
+		if result.Asset != config.XELIS_ASSET || result.Balance != 300 {
+			t.Errorf("unexpected result %+v", result)
+		}
+	case err := <-balanceErr:
+		t.Fatal(err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("balance_changed was not received")
+	}
+}
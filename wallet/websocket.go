@@ -1,6 +1,7 @@
 package wallet
 
 import (
+	"context"
 	"net/http"
 
 	"github.com/xelis-project/xelis-go-sdk/daemon"
@@ -27,6 +28,22 @@ func NewWebSocket(endpoint string, username string, password string) (*WebSocket
 	return daemonWS, nil
 }
 
+// Reconnects automatically and subscribes again to every event listened with the *Channel and *Func methods.
+func NewWebSocketWithReconnect(endpoint string, username string, password string, options rpc.ReconnectOptions) (*WebSocket, error) {
+	header := make(http.Header)
+	setAuthHeader(header, username, password)
+	ws, err := rpc.NewWebSocketWithReconnect(endpoint, header, options)
+	if err != nil {
+		return nil, err
+	}
+
+	walletWS := &WebSocket{
+		WS: ws,
+	}
+
+	return walletWS, nil
+}
+
 func (w *WebSocket) Close() error {
 	return w.WS.Close()
 }
@@ -201,119 +218,195 @@ func (w *WebSocket) OfflineFunc(onData func()) error {
 }
 
 func (w *WebSocket) GetVersion() (version string, err error) {
-	res, err := w.WS.Call(w.Prefix+GetVersion, nil)
+	return w.GetVersionContext(context.Background())
+}
+
+func (w *WebSocket) GetVersionContext(ctx context.Context) (version string, err error) {
+	res, err := w.WS.CallContext(ctx, w.Prefix+GetVersion, nil)
 	err = rpc.JsonFormatResponse(res, err, &version)
 	return
 }
 
 func (w *WebSocket) GetNetwork() (network string, err error) {
-	res, err := w.WS.Call(w.Prefix+GetNetwork, nil)
+	return w.GetNetworkContext(context.Background())
+}
+
+func (w *WebSocket) GetNetworkContext(ctx context.Context) (network string, err error) {
+	res, err := w.WS.CallContext(ctx, w.Prefix+GetNetwork, nil)
 	err = rpc.JsonFormatResponse(res, err, &network)
 	return
 }
 
 func (w *WebSocket) GetNonce() (nonce uint64, err error) {
-	res, err := w.WS.Call(w.Prefix+GetNonce, nil)
+	return w.GetNonceContext(context.Background())
+}
+
+func (w *WebSocket) GetNonceContext(ctx context.Context) (nonce uint64, err error) {
+	res, err := w.WS.CallContext(ctx, w.Prefix+GetNonce, nil)
 	err = rpc.JsonFormatResponse(res, err, &nonce)
 	return
 }
 
 func (w *WebSocket) GetTopoheight() (topoheight uint64, err error) {
-	res, err := w.WS.Call(w.Prefix+GetTopoheight, nil)
+	return w.GetTopoheightContext(context.Background())
+}
+
+func (w *WebSocket) GetTopoheightContext(ctx context.Context) (topoheight uint64, err error) {
+	res, err := w.WS.CallContext(ctx, w.Prefix+GetTopoheight, nil)
 	err = rpc.JsonFormatResponse(res, err, &topoheight)
 	return
 }
 
 func (w *WebSocket) GetAddress(params GetAddressParams) (address string, err error) {
-	res, err := w.WS.Call(w.Prefix+GetAddress, params)
+	return w.GetAddressContext(context.Background(), params)
+}
+
+func (w *WebSocket) GetAddressContext(ctx context.Context, params GetAddressParams) (address string, err error) {
+	res, err := w.WS.CallContext(ctx, w.Prefix+GetAddress, params)
 	err = rpc.JsonFormatResponse(res, err, &address)
 	return
 }
 
 func (w *WebSocket) SplitAddress(params SplitAddressParams) (result SplitAddressResult, err error) {
-	res, err := w.WS.Call(w.Prefix+SplitAddress, params)
+	return w.SplitAddressContext(context.Background(), params)
+}
+
+func (w *WebSocket) SplitAddressContext(ctx context.Context, params SplitAddressParams) (result SplitAddressResult, err error) {
+	res, err := w.WS.CallContext(ctx, w.Prefix+SplitAddress, params)
 	err = rpc.JsonFormatResponse(res, err, &result)
 	return
 }
 
 func (w *WebSocket) Rescan(params RescanParams) (success bool, err error) {
-	res, err := w.WS.Call(w.Prefix+Rescan, params)
+	return w.RescanContext(context.Background(), params)
+}
+
+func (w *WebSocket) RescanContext(ctx context.Context, params RescanParams) (success bool, err error) {
+	res, err := w.WS.CallContext(ctx, w.Prefix+Rescan, params)
 	err = rpc.JsonFormatResponse(res, err, &success)
 	return
 }
 
 func (w *WebSocket) GetBalance(params GetBalanceParams) (balance uint64, err error) {
-	res, err := w.WS.Call(w.Prefix+Rescan, params)
+	return w.GetBalanceContext(context.Background(), params)
+}
+
+func (w *WebSocket) GetBalanceContext(ctx context.Context, params GetBalanceParams) (balance uint64, err error) {
+	res, err := w.WS.CallContext(ctx, w.Prefix+GetBalance, params)
 	err = rpc.JsonFormatResponse(res, err, &balance)
 	return
 }
 
 func (w *WebSocket) HasBalance(params GetBalanceParams) (exists bool, err error) {
-	res, err := w.WS.Call(w.Prefix+HasBalance, params)
+	return w.HasBalanceContext(context.Background(), params)
+}
+
+func (w *WebSocket) HasBalanceContext(ctx context.Context, params GetBalanceParams) (exists bool, err error) {
+	res, err := w.WS.CallContext(ctx, w.Prefix+HasBalance, params)
 	err = rpc.JsonFormatResponse(res, err, &exists)
 	return
 }
 
 func (w *WebSocket) GetTrackedAssets() (assets []string, err error) {
-	res, err := w.WS.Call(w.Prefix+GetTrackedAssets, nil)
+	return w.GetTrackedAssetsContext(context.Background())
+}
+
+func (w *WebSocket) GetTrackedAssetsContext(ctx context.Context) (assets []string, err error) {
+	res, err := w.WS.CallContext(ctx, w.Prefix+GetTrackedAssets, nil)
 	err = rpc.JsonFormatResponse(res, err, &assets)
 	return
 }
 
 func (w *WebSocket) GetAssetPrecision(params GetAssetPrecisionParams) (decimals int, err error) {
-	res, err := w.WS.Call(w.Prefix+GetAssetPrecision, nil)
+	return w.GetAssetPrecisionContext(context.Background(), params)
+}
+
+func (w *WebSocket) GetAssetPrecisionContext(ctx context.Context, params GetAssetPrecisionParams) (decimals int, err error) {
+	res, err := w.WS.CallContext(ctx, w.Prefix+GetAssetPrecision, params)
 	err = rpc.JsonFormatResponse(res, err, &decimals)
 	return
 }
 
 func (w *WebSocket) GetTransaction(params GetTransactionParams) (transaction TransactionEntry, err error) {
-	res, err := w.WS.Call(w.Prefix+GetTransaction, params)
+	return w.GetTransactionContext(context.Background(), params)
+}
+
+func (w *WebSocket) GetTransactionContext(ctx context.Context, params GetTransactionParams) (transaction TransactionEntry, err error) {
+	res, err := w.WS.CallContext(ctx, w.Prefix+GetTransaction, params)
 	err = rpc.JsonFormatResponse(res, err, &transaction)
 	return
 }
 
 func (w *WebSocket) BuildTransaction(params BuildTransactionParams) (result BuildTransactionResult, err error) {
+	return w.BuildTransactionContext(context.Background(), params)
+}
+
+func (w *WebSocket) BuildTransactionContext(ctx context.Context, params BuildTransactionParams) (result BuildTransactionResult, err error) {
 	if err = checkFeeBuilder(params.Fee); err != nil {
 		return
 	}
 
-	res, err := w.WS.Call(w.Prefix+BuildTransaction, params)
+	res, err := w.WS.CallContext(ctx, w.Prefix+BuildTransaction, params)
 	err = rpc.JsonFormatResponse(res, err, &result)
 	return
 }
 
 func (w *WebSocket) ListTransactions(params ListTransactionsParams) (txs []TransactionEntry, err error) {
-	res, err := w.WS.Call(w.Prefix+ListTransactions, params)
+	return w.ListTransactionsContext(context.Background(), params)
+}
+
+func (w *WebSocket) ListTransactionsContext(ctx context.Context, params ListTransactionsParams) (txs []TransactionEntry, err error) {
+	res, err := w.WS.CallContext(ctx, w.Prefix+ListTransactions, params)
 	err = rpc.JsonFormatResponse(res, err, &txs)
 	return
 }
 
 func (w *WebSocket) IsOnline() (online bool, err error) {
-	res, err := w.WS.Call(w.Prefix+IsOnline, nil)
+	return w.IsOnlineContext(context.Background())
+}
+
+func (w *WebSocket) IsOnlineContext(ctx context.Context) (online bool, err error) {
+	res, err := w.WS.CallContext(ctx, w.Prefix+IsOnline, nil)
 	err = rpc.JsonFormatResponse(res, err, &online)
 	return
 }
 
 func (w *WebSocket) SetOnlineMode() (success bool, err error) {
-	res, err := w.WS.Call(w.Prefix+SetOnlineMode, nil)
+	return w.SetOnlineModeContext(context.Background())
+}
+
+func (w *WebSocket) SetOnlineModeContext(ctx context.Context) (success bool, err error) {
+	res, err := w.WS.CallContext(ctx, w.Prefix+SetOnlineMode, nil)
 	err = rpc.JsonFormatResponse(res, err, &success)
 	return
 }
 
 func (w *WebSocket) SetOfflineMode() (success bool, err error) {
-	res, err := w.WS.Call(w.Prefix+SetOfflineMode, nil)
+	return w.SetOfflineModeContext(context.Background())
+}
+
+func (w *WebSocket) SetOfflineModeContext(ctx context.Context) (success bool, err error) {
+	res, err := w.WS.CallContext(ctx, w.Prefix+SetOfflineMode, nil)
 	err = rpc.JsonFormatResponse(res, err, &success)
 	return
 }
 
 func (w *WebSocket) SignData(data interface{}) (signature string, err error) {
-	res, err := w.WS.Call(w.Prefix+SignData, data)
+	return w.SignDataContext(context.Background(), data)
+}
+
+func (w *WebSocket) SignDataContext(ctx context.Context, data interface{}) (signature string, err error) {
+	res, err := w.WS.CallContext(ctx, w.Prefix+SignData, data)
 	err = rpc.JsonFormatResponse(res, err, &signature)
 	return
 }
 
 func (w *WebSocket) EstimateFees(params EstimateFeesParams) (amount uint64, err error) {
-	res, err := w.WS.Call(w.Prefix+EstimateFees, params)
+	return w.EstimateFeesContext(context.Background(), params)
+}
+
+func (w *WebSocket) EstimateFeesContext(ctx context.Context, params EstimateFeesParams) (amount uint64, err error) {
+	res, err := w.WS.CallContext(ctx, w.Prefix+EstimateFees, params)
 	err = rpc.JsonFormatResponse(res, err, &amount)
 	return
 }
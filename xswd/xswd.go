@@ -2,7 +2,6 @@ package xswd
 
 import (
 	"encoding/json"
-	"fmt"
 
 	"github.com/xelis-project/xelis-go-sdk/daemon"
 	"github.com/xelis-project/xelis-go-sdk/rpc"
@@ -74,7 +73,7 @@ func (x *XSWD) Authorize(app ApplicationData) (res rpc.RPCResponse, err error) {
 	}
 
 	if res.Error != nil {
-		err = fmt.Errorf(res.Error.Message)
+		err = res.Error
 		return
 	}
 